@@ -0,0 +1,246 @@
+package hlg
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/dfirebaugh/hlg/pkg/bmfont"
+)
+
+// BitmapFont is a font made of pre-rendered glyph images, such as an AngelCode BMFont
+// or a fixed-grid pixel font. Unlike the MSDF Font it is never resampled, so it stays
+// crisp at the tiny resolutions pixel-art games use. Text is drawn through the textured
+// path (see BitmapText), which uses nearest filtering.
+//
+// Glyph coverage is read from the alpha channel of the page images and tinted with
+// the text color.
+type BitmapFont struct {
+	pages      []image.Image
+	glyphs     map[rune]bitmapGlyph
+	kernings   map[bmfont.KerningPair]int
+	lineHeight int
+	base       int
+}
+
+type bitmapGlyph struct {
+	page     int
+	rect     image.Rectangle // source rect on the page
+	xOffset  int
+	yOffset  int
+	xAdvance int
+}
+
+// LoadBMFont loads a BMFont descriptor (text, XML or binary) and the page images it
+// references. Page file names are resolved relative to the descriptor.
+func LoadBMFont(fntPath string) (*BitmapFont, error) {
+	data, err := os.ReadFile(fntPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font file: %w", err)
+	}
+	desc, err := bmfont.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font file: %w", err)
+	}
+
+	pages := make([]image.Image, len(desc.Pages))
+	for i, name := range desc.Pages {
+		pageData, err := os.ReadFile(filepath.Join(filepath.Dir(fntPath), name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read page %d: %w", i, err)
+		}
+		pages[i], _, err = image.Decode(bytes.NewReader(pageData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode page %d: %w", i, err)
+		}
+	}
+	return newBitmapFontFromDescriptor(desc, pages)
+}
+
+// LoadBMFontFromBytes creates a BitmapFont from descriptor bytes and already decoded
+// page images, given in page id order. This is the option for embedded assets and wasm.
+func LoadBMFontFromBytes(fntData []byte, pages []image.Image) (*BitmapFont, error) {
+	desc, err := bmfont.Parse(fntData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font file: %w", err)
+	}
+	return newBitmapFontFromDescriptor(desc, pages)
+}
+
+func newBitmapFontFromDescriptor(desc *bmfont.Font, pages []image.Image) (*BitmapFont, error) {
+	if len(pages) < len(desc.Pages) {
+		return nil, fmt.Errorf("font needs %d pages, got %d", len(desc.Pages), len(pages))
+	}
+
+	f := &BitmapFont{
+		pages:      pages,
+		glyphs:     make(map[rune]bitmapGlyph, len(desc.Chars)),
+		kernings:   desc.Kernings,
+		lineHeight: desc.LineHeight,
+		base:       desc.Base,
+	}
+	for id, c := range desc.Chars {
+		f.glyphs[id] = bitmapGlyph{
+			page:     c.Page,
+			rect:     image.Rect(c.X, c.Y, c.X+c.Width, c.Y+c.Height),
+			xOffset:  c.XOffset,
+			yOffset:  c.YOffset,
+			xAdvance: c.XAdvance,
+		}
+	}
+	return f, nil
+}
+
+// NewGridFont creates a BitmapFont from an image laid out as a grid of equally sized
+// cells. charset lists the characters in the grid from left to right, top to bottom.
+// Every glyph advances by the cell width.
+func NewGridFont(img image.Image, cellWidth, cellHeight int, charset string) (*BitmapFont, error) {
+	if cellWidth <= 0 || cellHeight <= 0 {
+		return nil, fmt.Errorf("invalid cell size %dx%d", cellWidth, cellHeight)
+	}
+	bounds := img.Bounds()
+	columns := bounds.Dx() / cellWidth
+	rows := bounds.Dy() / cellHeight
+	if columns == 0 || rows == 0 {
+		return nil, fmt.Errorf("image is smaller than one %dx%d cell", cellWidth, cellHeight)
+	}
+
+	f := &BitmapFont{
+		pages:      []image.Image{img},
+		glyphs:     make(map[rune]bitmapGlyph),
+		lineHeight: cellHeight,
+		base:       cellHeight,
+	}
+	i := 0
+	for _, r := range charset {
+		if i >= columns*rows {
+			break
+		}
+		x := bounds.Min.X + (i%columns)*cellWidth
+		y := bounds.Min.Y + (i/columns)*cellHeight
+		f.glyphs[r] = bitmapGlyph{
+			rect:     image.Rect(x, y, x+cellWidth, y+cellHeight),
+			xAdvance: cellWidth,
+		}
+		i++
+	}
+	return f, nil
+}
+
+// LineHeight returns the distance in pixels between consecutive lines.
+func (f *BitmapFont) LineHeight() int {
+	return f.lineHeight
+}
+
+// HasGlyph reports whether the font can render r.
+func (f *BitmapFont) HasGlyph(r rune) bool {
+	_, ok := f.glyphs[r]
+	return ok
+}
+
+// MeasureText returns the size in pixels of the text. For multiline text the width
+// is that of the longest line.
+func (f *BitmapFont) MeasureText(text string) (int, int) {
+	var maxWidth, width int
+	lines := 1
+	prev := rune(-1)
+	for _, r := range text {
+		if r == '\n' {
+			maxWidth = max(maxWidth, width)
+			width = 0
+			lines++
+			prev = -1
+			continue
+		}
+		g, ok := f.glyphs[r]
+		if !ok {
+			continue
+		}
+		width += f.kernings[bmfont.KerningPair{First: prev, Second: r}] + g.xAdvance
+		prev = r
+	}
+	return max(maxWidth, width), lines * f.lineHeight
+}
+
+// DrawToImage draws text onto dst with its top-left corner at (x, y).
+// This is the CPU path; it works with any draw.Image, including pkg/fb framebuffers.
+func (f *BitmapFont) DrawToImage(dst draw.Image, text string, x, y int, c color.Color) {
+	src := image.NewUniform(c)
+	cursorX, cursorY := x, y
+	prev := rune(-1)
+	for _, r := range text {
+		if r == '\n' {
+			cursorX = x
+			cursorY += f.lineHeight
+			prev = -1
+			continue
+		}
+		g, ok := f.glyphs[r]
+		if !ok {
+			continue
+		}
+		cursorX += f.kernings[bmfont.KerningPair{First: prev, Second: r}]
+		prev = r
+
+		dstRect := g.rect.Sub(g.rect.Min).Add(image.Pt(cursorX+g.xOffset, cursorY+g.yOffset))
+		draw.DrawMask(dst, dstRect, src, image.Point{}, f.pages[g.page], g.rect.Min, draw.Over)
+		cursorX += g.xAdvance
+	}
+}
+
+// RenderImage draws text into a new image sized to fit it.
+func (f *BitmapFont) RenderImage(text string, c color.Color) *image.RGBA {
+	w, h := f.MeasureText(text)
+	img := image.NewRGBA(image.Rect(0, 0, max(w, 1), max(h, 1)))
+	f.DrawToImage(img, text, 0, 0, c)
+	return img
+}
+
+// NewText creates a texture holding the rendered text.
+func (f *BitmapFont) NewText(text string, c color.Color) (*BitmapText, error) {
+	texture, err := CreateTextureFromImage(f.RenderImage(text, c))
+	if err != nil {
+		return nil, err
+	}
+	return &BitmapText{
+		Texture: texture,
+		font:    f,
+		text:    text,
+		color:   c,
+	}, nil
+}
+
+// BitmapText is text rendered with a BitmapFont into a texture.
+// Position and scale it like any other Texture; integer scales keep the pixels sharp.
+// The texture is only re-rendered when the text or color changes.
+type BitmapText struct {
+	*Texture
+	font  *BitmapFont
+	text  string
+	color color.Color
+}
+
+// Text returns the current text.
+func (t *BitmapText) Text() string {
+	return t.text
+}
+
+// SetText changes the text and re-renders the texture if it differs.
+func (t *BitmapText) SetText(text string) error {
+	if text == t.text {
+		return nil
+	}
+	t.text = text
+	return t.UpdateImage(t.font.RenderImage(t.text, t.color))
+}
+
+// SetColor changes the text color and re-renders the texture.
+func (t *BitmapText) SetColor(c color.Color) error {
+	t.color = c
+	return t.UpdateImage(t.font.RenderImage(t.text, t.color))
+}
@@ -0,0 +1,24 @@
+package hlg
+
+// clipboardText stands in for the system clipboard until a window is created, so
+// tools and tests that never open one can still copy and paste.
+var clipboardText string
+
+// GetClipboardText returns the text on the system clipboard. In browsers it is the text
+// last pasted into the page, or set by SetClipboardText, since reading the clipboard
+// directly needs the user's permission.
+func GetClipboardText() string {
+	if hlg.graphicsBackend == nil {
+		return clipboardText
+	}
+	return hlg.graphicsBackend.GetClipboardText()
+}
+
+// SetClipboardText puts text on the system clipboard.
+func SetClipboardText(text string) {
+	if hlg.graphicsBackend == nil {
+		clipboardText = text
+		return
+	}
+	hlg.graphicsBackend.SetClipboardText(text)
+}
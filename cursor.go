@@ -0,0 +1,36 @@
+package hlg
+
+import (
+	"image"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// SetCursorVisible shows or hides the mouse cursor while it is over the window.
+func SetCursorVisible(visible bool) {
+	if visible {
+		SetCursorMode(input.CursorNormal)
+	} else {
+		SetCursorMode(input.CursorHidden)
+	}
+}
+
+// SetCursorShape shows a standard cursor, such as input.CursorIBeam, over the window.
+func SetCursorShape(shape input.CursorShape) {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetCursorShape(shape)
+}
+
+// SetCursorImage shows img as the cursor over the window. (hotX, hotY) is the point in
+// the image that clicks, such as the tip of an arrow.
+func SetCursorImage(img image.Image, hotX, hotY int) {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetCursorImage(img, hotX, hotY)
+}
+
+// SetCursorMode shows, hides or locks the cursor. With input.CursorLocked the cursor is
+// hidden and kept in the window; read GetMouseDelta for mouse look.
+func SetCursorMode(mode input.CursorMode) {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetCursorMode(mode)
+}
@@ -0,0 +1,68 @@
+package hlg
+
+import (
+	"os"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// DroppedFile is a file dropped onto the window.
+type DroppedFile = input.DroppedFile
+
+var (
+	filesDroppedHandlers    []func(paths []string)
+	fileDataDroppedHandlers []func(files []DroppedFile)
+)
+
+// OnFilesDropped registers fn to receive the paths of files dropped onto the window.
+// Browsers do not reveal paths, so in wasm builds fn receives the file names; use
+// OnFileDataDropped to read dropped files the same way on every platform.
+func OnFilesDropped(fn func(paths []string)) {
+	filesDroppedHandlers = append(filesDroppedHandlers, fn)
+}
+
+// OnFileDataDropped registers fn to receive files dropped onto the window with their
+// contents, ready for CreateTextureFromImage, load.LoadOBJModelFromReader or
+// LoadFontFromBytes. On desktop the files are read from disk. Files that cannot be
+// read, such as directories, are left out.
+func OnFileDataDropped(fn func(files []DroppedFile)) {
+	fileDataDroppedHandlers = append(fileDataDroppedHandlers, fn)
+}
+
+// handleFilesDropped passes dropped files to the OnFilesDropped and OnFileDataDropped
+// handlers.
+func handleFilesDropped(files []DroppedFile) {
+	if len(filesDroppedHandlers) > 0 {
+		paths := make([]string, len(files))
+		for i, f := range files {
+			paths[i] = f.Path
+			if paths[i] == "" {
+				paths[i] = f.Name
+			}
+		}
+		for _, fn := range filesDroppedHandlers {
+			fn(paths)
+		}
+	}
+
+	if len(fileDataDroppedHandlers) == 0 {
+		return
+	}
+	withData := make([]DroppedFile, 0, len(files))
+	for _, f := range files {
+		if f.Data == nil && f.Path != "" {
+			data, err := os.ReadFile(f.Path)
+			if err != nil {
+				continue
+			}
+			f.Data = data
+		}
+		if f.Data == nil {
+			continue // the browser could not read it
+		}
+		withData = append(withData, f)
+	}
+	for _, fn := range fileDataDroppedHandlers {
+		fn(withData)
+	}
+}
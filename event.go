@@ -1,21 +1,92 @@
 package hlg
 
-import "github.com/dfirebaugh/hlg/pkg/input"
+import (
+	"slices"
+	"time"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+type eventHandler struct {
+	fn func(evt input.Event) bool
+}
+
+var eventHandlers []*eventHandler
+
+// OnEvent registers fn to receive each input event as it arrives, in order, before it
+// updates the input state. Handlers run in the order they were registered. A handler
+// returns true to consume the event: later handlers, Events and the polling functions
+// such as IsKeyPressed then do not see it, so a UI can keep its clicks from reaching
+// the game. Consume a press and its release together, or the key stays held.
+//
+// OnEvent returns a function that removes the handler.
+func OnEvent(fn func(evt input.Event) bool) (remove func()) {
+	h := &eventHandler{fn: fn}
+	eventHandlers = append(eventHandlers, h)
+	return func() {
+		eventHandlers = slices.DeleteFunc(eventHandlers, func(o *eventHandler) bool { return o == h })
+	}
+}
+
+// Events returns the input events received this frame, in order, except those
+// consumed by OnEvent handlers. Unlike the polling functions it keeps every press of
+// a key pressed twice in one frame. The slice is reused after the frame.
+func Events() []input.Event {
+	return hlg.inputState.GetEvents()
+}
+
+// dispatchEvent passes an event from the graphics backend to the OnEvent handlers and,
+// unless one consumes it, to the input state.
+func dispatchEvent(evt input.Event, state *input.InputState) {
+	if evt.Time.IsZero() {
+		evt.Time = time.Now()
+	}
+	// Handlers may remove themselves while handling
+	for _, h := range slices.Clone(eventHandlers) {
+		if h.fn(evt) {
+			return
+		}
+	}
+	state.AddEvent(evt)
+	handleEvent(evt, state)
+}
 
 func handleEvent(evt input.Event, state *input.InputState) {
 	switch evt.Type {
 	case input.KeyPress:
-		state.PressKey(evt.Key)
+		if evt.Repeat {
+			state.RepeatKey(evt.Key)
+		} else {
+			state.PressKey(evt.Key)
+		}
+		state.SetKeyModifiers(evt.Mods, evt.Key)
 	case input.KeyRelease:
 		state.ReleaseKey(evt.Key)
+		state.SetKeyModifiers(evt.Mods, evt.Key)
 	case input.MousePress:
+		state.SetModifiers(evt.Mods)
 		state.PressButton(evt.MouseButton)
 	case input.MouseRelease:
+		state.SetModifiers(evt.Mods)
 		state.ReleaseButton(evt.MouseButton)
 	case input.MouseMove:
 		state.CursorPosition.X = evt.X
 		state.CursorPosition.Y = evt.Y
+		state.AddMouseDelta(evt.DX, evt.DY)
 	case input.CharInput:
 		state.AddTypedRune(evt.Rune)
+	case input.TouchStart:
+		state.StartTouch(evt.TouchID, evt.X, evt.Y, evt.Time)
+	case input.TouchMove:
+		state.MoveTouch(evt.TouchID, evt.X, evt.Y)
+	case input.TouchEnd, input.TouchCancel:
+		state.EndTouch(evt.TouchID, evt.Type == input.TouchCancel)
+	case input.FilesDropped:
+		handleFilesDropped(evt.Files)
+	case input.MouseScroll:
+		state.AddScroll(evt.ScrollX, evt.ScrollY)
+		if state.ScrollCallback != nil {
+			state.ScrollCallback(evt.ScrollX, evt.ScrollY)
+		}
 	}
 }
@@ -93,29 +93,24 @@ func main() {
 
 		// Draggable panel
 		if ctx.Panel("Ball Control", &panelState, panelW, panelH) {
-			// Panel content (only rendered if not collapsed)
-			px, py := panelState.X, panelState.Y
-
-			// Slider
-			ctx.Slider("ball_count", &sliderValue, 0, 100, px+10, py+40, 240, 12)
-
-			// Fade toggle
-			hlg.Text("Enable Color Fade", px+10, py+75, 14, colornames.White)
-			ctx.Toggle("fade", &enableFade, px+200, py+70, 40, 22)
-
-			// Collision toggle
-			hlg.Text("Enable Collision", px+10, py+105, 14, colornames.White)
-			ctx.Toggle("collision", &enableCollide, px+200, py+100, 40, 22)
-
-			// Reset button
-			if ctx.Button("Reset Balls", px+10, py+135, 240, 28) {
+			// Panel content flows down the panel body (only rendered if not collapsed)
+			ctx.AddSlider("ball_count", &sliderValue, 0, 100)
+
+			ctx.BeginRow(gui.Fill(), gui.Auto())
+			ctx.AddLabel("Enable Color Fade")
+			ctx.AddToggle("fade", &enableFade)
+			ctx.AddLabel("Enable Collision")
+			ctx.AddToggle("collision", &enableCollide)
+			ctx.EndRow()
+
+			ctx.SetNextWidth(gui.Fill())
+			if ctx.AddButton("Reset Balls") {
 				for i := range balls {
 					balls[i] = NewBall()
 				}
 			}
 
-			// Ball count label
-			hlg.Text(fmt.Sprintf("Number of Balls: %d", numBalls), px+10, py+175, 14, colornames.White)
+			ctx.AddLabel(fmt.Sprintf("Number of Balls: %d", numBalls))
 		}
 
 		ctx.End()
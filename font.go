@@ -11,6 +11,7 @@ import (
 	_ "image/png"
 	"math"
 	"os"
+	"strings"
 
 	"github.com/dfirebaugh/hlg/graphics"
 	"github.com/dfirebaugh/msdf/msdf"
@@ -73,6 +74,14 @@ type Font struct {
 
 	// Cached space glyph for missing character fallback
 	spaceGlyph *graphics.GlyphInfo
+
+	// Fonts searched for glyphs missing from this font's atlas
+	fallbacks []*Font
+
+	// Shapes text before glyph lookup, nil when shaping is disabled
+	shaper *textShaper
+	// Shaped lines by text and size, so unchanged text is not shaped every frame
+	layouts map[layoutKey][]shapedGlyph
 }
 
 // LoadFont loads a font from a file path and generates an MSDF atlas
@@ -132,6 +141,7 @@ type AtlasMetadata struct {
 	} `json:"metrics"`
 	Glyphs []struct {
 		Unicode     int     `json:"unicode"`
+		Index       *int    `json:"index,omitempty"` // set when the atlas was generated by glyph index
 		Advance     float64 `json:"advance"`
 		PlaneBounds *struct {
 			Left   float64 `json:"left"`
@@ -231,7 +241,12 @@ func LoadFontFromAtlas(atlasPNGPath, atlasJSONPath string) (*Font, error) {
 			}
 		}
 
-		atlas.AddGlyph(rune(g.Unicode), info)
+		if g.Index != nil {
+			atlas.AddGlyphByIndex(*g.Index, info)
+		}
+		if g.Unicode != 0 || g.Index == nil {
+			atlas.AddGlyph(rune(g.Unicode), info)
+		}
 	}
 
 	// Determine pixel range from atlas type
@@ -341,6 +356,10 @@ func createFontFromData(fontData []byte, config FontConfig) (*Font, error) {
 	font.fontData = fontData
 	font.config = config
 
+	if err := font.EnableShaping(fontData); err != nil {
+		return nil, err
+	}
+
 	return font, nil
 }
 
@@ -458,57 +477,56 @@ func (f *Font) SaveAtlasDebug(filename string) error {
 // This method requires that SetMSDFAtlas has been called with this font's atlas first.
 func (f *Font) DrawText(dc graphics.GlyphDrawer, text string, x, y, fontSize float32, c color.Color) {
 	metrics := f.atlas.GetMetrics()
-	advanceScale := float32(float64(fontSize) / metrics.EmSize)
+	// Glyphs from fallback fonts need a drawer that can select an atlas slot.
+	// If dc cannot, they are drawn straight into the current frame batch.
+	atlasDC, ok := dc.(graphics.AtlasGlyphDrawer)
+	if !ok {
+		atlasDC = FrameGlyphDrawer{}
+	}
 
-	cursorX := x
 	// Position Y is treated as the top of the text area (cap height), so we add
 	// an approximation of cap height to get the baseline position.
 	// Cap height is typically ~70-75% of ascender for most fonts.
 	capHeightScale := float32(metrics.Ascender / metrics.EmSize * 0.68)
-	cursorY := y + capHeightScale*fontSize
+	baselineY := y + capHeightScale*fontSize
 
-	for _, ch := range text {
-		if ch == '\n' {
-			cursorX = x
-			cursorY += fontSize * 1.2
-			continue
-		}
+	for lineIndex, line := range strings.Split(text, "\n") {
+		for _, sg := range f.layoutLine([]rune(line), fontSize) {
+			if sg.glyph == nil {
+				// Characters missing from the whole chain only take up space
+				continue
+			}
 
-		glyph := f.atlas.GetGlyph(ch)
-		if glyph == nil {
-			// Use cached space glyph advance for missing characters
-			if f.spaceGlyph != nil {
-				cursorX += float32(f.spaceGlyph.Quad.Advance) * advanceScale
+			q := sg.glyph.Quad
+			cursorX := x + sg.x
+			cursorY := baselineY + float32(lineIndex)*fontSize*1.2 + sg.y
+
+			// Calculate screen positions for this glyph
+			// Pixel-perfect rounding: only round top-left corner, add unrounded dimensions
+			// This preserves exact glyph size while snapping to pixel grid (banana-c approach)
+			glyphX := float32(math.Floor(float64(cursorX+float32(q.PL)*fontSize) + 0.5))
+			glyphY := float32(math.Floor(float64(cursorY-float32(q.PT)*fontSize) + 0.5))
+			glyphW := float32(q.PR-q.PL) * fontSize
+			glyphH := float32(q.PT-q.PB) * fontSize
+
+			x0 := glyphX
+			y0 := glyphY
+			x1 := glyphX + glyphW
+			y1 := glyphY + glyphH
+
+			// UV coordinates from atlas
+			u0 := float32(q.S0)
+			v0 := float32(q.T0)
+			u1 := float32(q.S1)
+			v1 := float32(q.T1)
+
+			// Draw the glyph quad
+			if sg.slot == 0 {
+				dc.DrawGlyph(x0, y0, x1, y1, u0, v0, u1, v1, c)
+			} else {
+				atlasDC.DrawAtlasGlyph(x0, y0, x1, y1, u0, v0, u1, v1, sg.slot, c)
 			}
-			continue
 		}
-
-		q := glyph.Quad
-
-		// Calculate screen positions for this glyph
-		// Pixel-perfect rounding: only round top-left corner, add unrounded dimensions
-		// This preserves exact glyph size while snapping to pixel grid (banana-c approach)
-		glyphX := float32(math.Floor(float64(cursorX+float32(q.PL)*fontSize) + 0.5))
-		glyphY := float32(math.Floor(float64(cursorY-float32(q.PT)*fontSize) + 0.5))
-		glyphW := float32(q.PR-q.PL) * fontSize
-		glyphH := float32(q.PT-q.PB) * fontSize
-
-		x0 := glyphX
-		y0 := glyphY
-		x1 := glyphX + glyphW
-		y1 := glyphY + glyphH
-
-		// UV coordinates from atlas
-		u0 := float32(q.S0)
-		v0 := float32(q.T0)
-		u1 := float32(q.S1)
-		v1 := float32(q.T1)
-
-		// Draw the glyph quad
-		dc.DrawGlyph(x0, y0, x1, y1, u0, v0, u1, v1, c)
-
-		// Advance cursor for next glyph
-		cursorX += float32(q.Advance) * advanceScale
 	}
 }
 
@@ -517,12 +535,10 @@ func (f *Font) DrawText(dc graphics.GlyphDrawer, text string, x, y, fontSize flo
 // DEPRECATED: Use RenderTextPrimitives for ~5x memory reduction.
 func (f *Font) RenderText(text string, x, y, fontSize float32, c color.Color, screenWidth, screenHeight int) []graphics.PrimitiveVertex {
 	metrics := f.atlas.GetMetrics()
-	advanceScale := float32(float64(fontSize) / metrics.EmSize)
 
-	cursorX := x
 	// Use cap height approximation for more intuitive Y positioning
 	capHeightScale := float32(metrics.Ascender / metrics.EmSize * 0.68)
-	cursorY := y + capHeightScale*fontSize
+	baselineY := y + capHeightScale*fontSize
 
 	// Pre-allocate vertices (6 vertices per glyph for 2 triangles)
 	vertices := make([]graphics.PrimitiveVertex, 0, len(text)*6)
@@ -539,57 +555,52 @@ func (f *Font) RenderText(text string, x, y, fontSize float32, c color.Color, sc
 		float32(a) / 0xffff,
 	}
 
-	for _, ch := range text {
-		if ch == '\n' {
-			cursorX = x
-			cursorY += fontSize * 1.2
-			continue
-		}
-
-		glyph := f.atlas.GetGlyph(ch)
-		if glyph == nil {
-			if f.spaceGlyph != nil {
-				cursorX += float32(f.spaceGlyph.Quad.Advance) * advanceScale
+	for lineIndex, line := range strings.Split(text, "\n") {
+		for _, sg := range f.layoutLine([]rune(line), fontSize) {
+			if sg.glyph == nil {
+				// Characters missing from the whole chain only take up space
+				continue
 			}
-			continue
-		}
-
-		q := glyph.Quad
-
-		// Pixel-perfect rounding: only round top-left corner, add unrounded dimensions
-		glyphX := float32(math.Floor(float64(cursorX+float32(q.PL)*fontSize) + 0.5))
-		glyphY := float32(math.Floor(float64(cursorY-float32(q.PT)*fontSize) + 0.5))
-		glyphW := float32(q.PR-q.PL) * fontSize
-		glyphH := float32(q.PT-q.PB) * fontSize
-
-		x0 := glyphX
-		y0 := glyphY
-		x1 := glyphX + glyphW
-		y1 := glyphY + glyphH
-
-		// UV coordinates
-		u0 := float32(q.S0)
-		v0 := float32(q.T0)
-		u1 := float32(q.S1)
-		v1 := float32(q.T1)
-
-		// Convert to NDC
-		ndcX0 := (x0/sw)*2.0 - 1.0
-		ndcY0 := 1.0 - (y0/sh)*2.0
-		ndcX1 := (x1/sw)*2.0 - 1.0
-		ndcY1 := 1.0 - (y1/sh)*2.0
 
-		// Create 6 vertices for 2 triangles
-		vertices = append(vertices,
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u0, v0}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u0, v1}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u1, v0}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u0, v1}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u1, v1}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u1, v0}},
-		)
-
-		cursorX += float32(q.Advance) * advanceScale
+			q := sg.glyph.Quad
+			cursorX := x + sg.x
+			cursorY := baselineY + float32(lineIndex)*fontSize*1.2 + sg.y
+
+			// Pixel-perfect rounding: only round top-left corner, add unrounded dimensions
+			glyphX := float32(math.Floor(float64(cursorX+float32(q.PL)*fontSize) + 0.5))
+			glyphY := float32(math.Floor(float64(cursorY-float32(q.PT)*fontSize) + 0.5))
+			glyphW := float32(q.PR-q.PL) * fontSize
+			glyphH := float32(q.PT-q.PB) * fontSize
+
+			x0 := glyphX
+			y0 := glyphY
+			x1 := glyphX + glyphW
+			y1 := glyphY + glyphH
+
+			// UV coordinates
+			u0 := float32(q.S0)
+			v0 := float32(q.T0)
+			u1 := float32(q.S1)
+			v1 := float32(q.T1)
+
+			// Convert to NDC
+			atlasSlot := float32(sg.slot)
+
+			ndcX0 := (x0/sw)*2.0 - 1.0
+			ndcY0 := 1.0 - (y0/sh)*2.0
+			ndcX1 := (x1/sw)*2.0 - 1.0
+			ndcY1 := 1.0 - (y1/sh)*2.0
+
+			// Create 6 vertices for 2 triangles
+			vertices = append(vertices,
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u0, v0}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u0, v1}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u1, v0}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u0, v1}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u1, v1}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u1, v0}},
+			)
+		}
 	}
 
 	return vertices
@@ -597,14 +608,13 @@ func (f *Font) RenderText(text string, x, y, fontSize float32, c color.Color, sc
 
 // RenderTextPrimitives renders text and returns Primitives directly (one per glyph).
 // This is the efficient storage buffer approach using 64 bytes per glyph instead of 312 bytes.
+// If shaping is enabled the text is shaped first; see EnableShaping.
 func (f *Font) RenderTextPrimitives(text string, x, y, fontSize float32, c color.Color) []graphics.Primitive {
 	metrics := f.atlas.GetMetrics()
-	advanceScale := float32(float64(fontSize) / metrics.EmSize)
 
-	cursorX := x
 	// Use cap height approximation for more intuitive Y positioning
 	capHeightScale := float32(metrics.Ascender / metrics.EmSize * 0.68)
-	cursorY := y + capHeightScale*fontSize
+	baselineY := y + capHeightScale*fontSize
 
 	// Pre-allocate primitives (1 per glyph)
 	primitives := make([]graphics.Primitive, 0, len(text))
@@ -618,100 +628,59 @@ func (f *Font) RenderTextPrimitives(text string, x, y, fontSize float32, c color
 		float32(a) / 0xffff,
 	}
 
-	for _, ch := range text {
-		if ch == '\n' {
-			cursorX = x
-			cursorY += fontSize * 1.2
-			continue
-		}
-
-		glyph := f.atlas.GetGlyph(ch)
-		if glyph == nil {
-			if f.spaceGlyph != nil {
-				cursorX += float32(f.spaceGlyph.Quad.Advance) * advanceScale
+	for lineIndex, line := range strings.Split(text, "\n") {
+		for _, sg := range f.layoutLine([]rune(line), fontSize) {
+			if sg.glyph == nil {
+				// Characters missing from the whole chain only take up space
+				continue
 			}
-			continue
-		}
 
-		q := glyph.Quad
-
-		// Pixel-perfect rounding: only round top-left corner, add unrounded dimensions
-		// This preserves exact glyph size while snapping to pixel grid (banana-c approach)
-		// Pixel-perfect rounding: only round top-left corner, add unrounded dimensions
-		glyphX := float32(math.Floor(float64(cursorX+float32(q.PL)*fontSize) + 0.5))
-		glyphY := float32(math.Floor(float64(cursorY-float32(q.PT)*fontSize) + 0.5))
-		glyphW := float32(q.PR-q.PL) * fontSize
-		glyphH := float32(q.PT-q.PB) * fontSize
-
-		// UV coordinates
-		u0 := float32(q.S0)
-		v0 := float32(q.T0)
-		u1 := float32(q.S1)
-		v1 := float32(q.T1)
-
-		// Skip glyphs with invalid sizes
-		if glyphW <= 0 || glyphH <= 0 {
-			cursorX += float32(q.Advance) * advanceScale
-			continue
-		}
+			q := sg.glyph.Quad
+			cursorX := x + sg.x
+			cursorY := baselineY + float32(lineIndex)*fontSize*1.2 + sg.y
 
-		primitives = append(primitives, graphics.Primitive{
-			X:      glyphX,
-			Y:      glyphY,
-			W:      glyphW,
-			H:      glyphH,
-			Color:  colorVec,
-			Radius: 0,
-			OpCode: graphics.OpCodeMSDF,
-			Extra:  [4]float32{u0, v0, u1 - u0, v1 - v0}, // UV: base + size
-		})
+			// Pixel-perfect rounding: only round top-left corner, add unrounded dimensions
+			// This preserves exact glyph size while snapping to pixel grid (banana-c approach)
+			glyphX := float32(math.Floor(float64(cursorX+float32(q.PL)*fontSize) + 0.5))
+			glyphY := float32(math.Floor(float64(cursorY-float32(q.PT)*fontSize) + 0.5))
+			glyphW := float32(q.PR-q.PL) * fontSize
+			glyphH := float32(q.PT-q.PB) * fontSize
 
-		cursorX += float32(q.Advance) * advanceScale
+			// Skip glyphs with invalid sizes
+			if glyphW <= 0 || glyphH <= 0 {
+				continue
+			}
+
+			// UV coordinates
+			u0 := float32(q.S0)
+			v0 := float32(q.T0)
+			u1 := float32(q.S1)
+			v1 := float32(q.T1)
+
+			primitives = append(primitives, graphics.Primitive{
+				X:         glyphX,
+				Y:         glyphY,
+				W:         glyphW,
+				H:         glyphH,
+				Color:     colorVec,
+				Radius:    0,
+				OpCode:    graphics.OpCodeMSDF,
+				AtlasSlot: float32(sg.slot),
+				Extra:     [4]float32{u0, v0, u1 - u0, v1 - v0}, // UV: base + size
+			})
+		}
 	}
 
 	return primitives
 }
 
-// SetAsActiveAtlas sets this font's atlas as the active MSDF atlas for the primitive buffer.
-// This must be called before using DrawText.
-func (f *Font) SetAsActiveAtlas() {
-	SetMSDFAtlas(f.atlasImage, f.config.PixelRange)
-}
-
 // MeasureText returns the width of the text in pixels at the given font size.
 // For multiline text, returns the width of the longest line.
 func (f *Font) MeasureText(text string, fontSize float32) float32 {
-	metrics := f.atlas.GetMetrics()
-	advanceScale := float32(float64(fontSize) / metrics.EmSize)
-
 	var maxWidth float32
-	var currentWidth float32
-	for _, ch := range text {
-		if ch == '\n' {
-			if currentWidth > maxWidth {
-				maxWidth = currentWidth
-			}
-			currentWidth = 0
-			continue
-		}
-
-		glyph := f.atlas.GetGlyph(ch)
-		if glyph == nil {
-			// Use cached space glyph advance for missing characters
-			if f.spaceGlyph != nil {
-				currentWidth += float32(f.spaceGlyph.Quad.Advance) * advanceScale
-			}
-			continue
-		}
-
-		currentWidth += float32(glyph.Quad.Advance) * advanceScale
+	for _, line := range strings.Split(text, "\n") {
+		maxWidth = max(maxWidth, lineWidth(f.layoutLine([]rune(line), fontSize)))
 	}
-
-	// Check final line
-	if currentWidth > maxWidth {
-		maxWidth = currentWidth
-	}
-
 	return maxWidth
 }
 
@@ -0,0 +1,132 @@
+package hlg
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Text indices used by IndexAtPoint and CaretRect are byte offsets into the text that
+// always fall on a UTF-8 character boundary, so they can be used to slice the string.
+// Coordinates are relative to the position the text is drawn at.
+
+// IndexAtPoint returns the index of the caret position closest to (x, y).
+// Lines are fontSize*1.2 pixels apart, matching DrawText; points above or below
+// the text resolve to the first or last line.
+func (f *Font) IndexAtPoint(text string, fontSize, x, y float32) int {
+	lines := strings.Split(text, "\n")
+	lineIndex := int(math.Floor(float64(y / (fontSize * 1.2))))
+	lineIndex = min(max(lineIndex, 0), len(lines)-1)
+
+	lineStart := 0
+	for _, line := range lines[:lineIndex] {
+		lineStart += len(line) + 1
+	}
+
+	line := []rune(lines[lineIndex])
+	carets, _ := f.caretPositions(line, fontSize)
+	best := 0
+	for i, cx := range carets {
+		if abs32(cx-x) < abs32(carets[best]-x) {
+			best = i
+		}
+	}
+	return lineStart + len(string(line[:best]))
+}
+
+// CaretRect returns the rectangle of the caret placed before the character at index.
+// The rectangle spans the full line height; its width is the advance of the character
+// at index, or zero at the end of a line, which suits both thin and block carets.
+// Selections can be highlighted by joining the rects of their end points on each line.
+// Indices inside a multi-byte character snap to the start of that character.
+func (f *Font) CaretRect(text string, fontSize float32, index int) (x, y, w, h float32) {
+	index = min(max(index, 0), len(text))
+	for index > 0 && index < len(text) && !utf8.RuneStart(text[index]) {
+		index--
+	}
+
+	lineIndex := strings.Count(text[:index], "\n")
+	lineStart := strings.LastIndexByte(text[:index], '\n') + 1
+	lineEnd := strings.IndexByte(text[lineStart:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(text)
+	} else {
+		lineEnd += lineStart
+	}
+
+	line := []rune(text[lineStart:lineEnd])
+	runeIndex := utf8.RuneCountInString(text[lineStart:index])
+	carets, widths := f.caretPositions(line, fontSize)
+
+	lineHeight := fontSize * 1.2
+	return carets[runeIndex], float32(lineIndex) * lineHeight, widths[runeIndex], lineHeight
+}
+
+// caretPositions returns, for each rune index in the line and the end of the line,
+// the x position of the caret before that rune and the advance of that rune.
+func (f *Font) caretPositions(line []rune, fontSize float32) (carets, widths []float32) {
+	carets = make([]float32, len(line)+1)
+	widths = make([]float32, len(line)+1)
+	if len(line) == 0 {
+		return carets, widths
+	}
+
+	// Extent of each cluster, keyed by the index of its first rune
+	type extent struct {
+		left, right float32
+		rtl         bool
+	}
+	clusters := make(map[int]*extent)
+	for _, g := range f.layoutLine(line, fontSize) {
+		e, ok := clusters[g.cluster]
+		if !ok {
+			clusters[g.cluster] = &extent{left: g.x, right: g.x + g.advance, rtl: g.rtl}
+			continue
+		}
+		e.left = min(e.left, g.x)
+		e.right = max(e.right, g.x+g.advance)
+	}
+	starts := make([]int, 0, len(clusters))
+	for start := range clusters {
+		starts = append(starts, start)
+	}
+	sort.Ints(starts)
+
+	// Split each cluster evenly between the runes it was shaped from, such as the letters of a ligature
+	rtl := make([]bool, len(line))
+	for i, start := range starts {
+		end := len(line)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		e := clusters[start]
+		step := (e.right - e.left) / float32(end-start)
+		for r := start; r < end; r++ {
+			k := float32(r - start)
+			rtl[r] = e.rtl
+			widths[r] = step
+			if e.rtl {
+				carets[r] = e.right - k*step
+			} else {
+				carets[r] = e.left + k*step
+			}
+		}
+	}
+
+	// The caret at the end of the line sits on the trailing edge of the last character
+	last := len(line) - 1
+	if rtl[last] {
+		carets[len(line)] = carets[last] - widths[last]
+	} else {
+		carets[len(line)] = carets[last] + widths[last]
+	}
+	return carets, widths
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
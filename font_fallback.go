@@ -0,0 +1,85 @@
+package hlg
+
+import (
+	"github.com/dfirebaugh/hlg/graphics"
+)
+
+// activeAtlasFont is the font whose atlas chain was last uploaded with SetAsActiveAtlas.
+var activeAtlasFont *Font
+
+// SetFallbackFonts sets the fonts searched, in order, for glyphs missing from this font.
+// Each fallback occupies its own MSDF atlas slot, so at most graphics.MaxMSDFAtlasSlots-1
+// fallbacks are used; any beyond that are ignored. Fallbacks of fallback fonts are not searched.
+func (f *Font) SetFallbackFonts(fonts ...*Font) {
+	f.fallbacks = f.fallbacks[:0]
+	for _, fb := range fonts {
+		if fb == nil || fb == f {
+			continue
+		}
+		if len(f.fallbacks) == graphics.MaxMSDFAtlasSlots-1 {
+			break
+		}
+		f.fallbacks = append(f.fallbacks, fb)
+	}
+	// Clusters missing from the atlas are laid out from the fallbacks
+	f.layouts = nil
+
+	if activeAtlasFont == f {
+		f.SetAsActiveAtlas()
+	}
+}
+
+// AddFallbackFont appends a font to the end of the fallback chain.
+func (f *Font) AddFallbackFont(fb *Font) {
+	f.SetFallbackFonts(append(f.GetFallbackFonts(), fb)...)
+}
+
+// GetFallbackFonts returns the fonts in the fallback chain.
+func (f *Font) GetFallbackFonts() []*Font {
+	return append([]*Font(nil), f.fallbacks...)
+}
+
+// HasGlyph reports whether this font or one of its fallbacks can render r.
+func (f *Font) HasGlyph(r rune) bool {
+	glyph, _, _ := f.lookupGlyph(r)
+	return glyph != nil
+}
+
+// SetAsActiveAtlas sets this font's atlas as the active MSDF atlas for the primitive buffer,
+// and loads the atlases of its fallback fonts into the following atlas slots.
+// This must be called before using DrawText.
+func (f *Font) SetAsActiveAtlas() {
+	SetMSDFAtlas(f.atlasImage, f.config.PixelRange)
+	for i, fb := range f.fallbacks {
+		SetMSDFAtlasSlot(i+1, fb.atlasImage, fb.config.PixelRange)
+	}
+	activeAtlasFont = f
+}
+
+// lookupGlyph finds the glyph for r in this font or its fallback chain.
+// It returns the glyph, the font that owns it and the atlas slot of that font.
+// If no font has the glyph, it returns a nil glyph owned by f.
+func (f *Font) lookupGlyph(r rune) (*graphics.GlyphInfo, *Font, int) {
+	if glyph := f.atlas.GetGlyph(r); glyph != nil {
+		return glyph, f, 0
+	}
+	for i, fb := range f.fallbacks {
+		if glyph := fb.atlas.GetGlyph(r); glyph != nil {
+			return glyph, fb, i + 1
+		}
+	}
+	return nil, f, 0
+}
+
+// advanceScale converts glyph advances from this font's atlas units to pixels.
+func (f *Font) advanceScale(fontSize float32) float32 {
+	return float32(float64(fontSize) / f.atlas.GetMetrics().EmSize)
+}
+
+// missingGlyphAdvance returns the advance used for characters no font in the chain can render.
+func (f *Font) missingGlyphAdvance(fontSize float32) float32 {
+	if f.spaceGlyph == nil {
+		return 0
+	}
+	return float32(f.spaceGlyph.Quad.Advance) * f.advanceScale(fontSize)
+}
@@ -0,0 +1,273 @@
+package hlg
+
+import (
+	"bytes"
+	"fmt"
+	"unicode"
+
+	"github.com/dfirebaugh/hlg/graphics"
+	"github.com/go-text/typesetting/di"
+	"github.com/go-text/typesetting/font"
+	"github.com/go-text/typesetting/shaping"
+	"golang.org/x/image/math/fixed"
+)
+
+// textShaper turns a line of runes into positioned glyph indices using
+// the HarfBuzz port from go-text/typesetting.
+type textShaper struct {
+	face      *font.Face
+	harfbuzz  shaping.HarfbuzzShaper
+	segmenter shaping.Segmenter
+}
+
+// singleFace resolves every rune to the same face.
+// Runes the face cannot render fall back to the atlas chain after shaping.
+type singleFace struct {
+	face *font.Face
+}
+
+func (s singleFace) ResolveFace(r rune) *font.Face {
+	return s.face
+}
+
+// shapedGlyph is one glyph of a laid out line, in visual order.
+type shapedGlyph struct {
+	glyph   *graphics.GlyphInfo // nil for characters no font in the chain can render
+	slot    int                 // atlas slot of the font that owns glyph
+	x, y    float32             // pen position relative to the line origin in pixels, y grows down
+	advance float32
+	cluster int  // index in the line of the first rune this glyph was shaped from
+	rtl     bool // the glyph belongs to a right-to-left run
+}
+
+// EnableShaping parses ttfData, the font file the atlas was generated from, and uses it
+// to shape text before glyph lookup. Shaping applies kerning, ligatures, mark positioning
+// and the contextual forms of complex scripts such as Arabic and Devanagari, and lays out
+// mixed left-to-right and right-to-left text in visual order.
+//
+// Shaped glyphs are looked up by glyph index. Atlases generated by glyph index provide
+// every glyph directly; for atlases generated from a charset only glyphs reachable from
+// a character are known, so include presentation forms in the charset to get contextual
+// forms. Clusters whose glyphs are missing from the atlas are drawn character by character
+// through the fallback chain.
+//
+// Fonts loaded from TTF data on desktop have shaping enabled automatically, and fail to
+// load if the font cannot be parsed for shaping. Fonts loaded from a pregenerated atlas
+// need this to be called with the matching font file.
+//
+// Shaped lines are cached by text and size, so text drawn every frame is shaped once.
+func (f *Font) EnableShaping(ttfData []byte) error {
+	face, err := font.ParseTTF(bytes.NewReader(ttfData))
+	if err != nil {
+		return fmt.Errorf("failed to parse font for shaping: %w", err)
+	}
+
+	// Key the atlas glyphs by the glyph index their character maps to
+	iter := face.Cmap.Iter()
+	for iter.Next() {
+		r, gid := iter.Char()
+		if glyph := f.atlas.GetGlyph(r); glyph != nil && f.atlas.GetGlyphByIndex(int(gid)) == nil {
+			f.atlas.AddGlyphByIndex(int(gid), glyph)
+		}
+	}
+
+	f.shaper = &textShaper{face: face}
+	f.layouts = nil
+	return nil
+}
+
+// DisableShaping returns the font to looking glyphs up character by character.
+func (f *Font) DisableShaping() {
+	f.shaper = nil
+	f.layouts = nil
+}
+
+// ShapingEnabled reports whether text is shaped before glyph lookup.
+func (f *Font) ShapingEnabled() bool {
+	return f.shaper != nil
+}
+
+// maxCachedLayouts bounds the shaped lines a font keeps; the cache starts over when full.
+const maxCachedLayouts = 1024
+
+// layoutKey identifies a shaped line in a font's cache.
+type layoutKey struct {
+	line     string
+	fontSize float32
+}
+
+// layoutLine positions the glyphs of a single line of text.
+// The line must not contain newlines. The glyphs returned must not be modified, since
+// shaped lines are cached.
+func (f *Font) layoutLine(line []rune, fontSize float32) []shapedGlyph {
+	if f.shaper == nil || len(line) == 0 {
+		return f.layoutRunes(line, 0, len(line), 0, fontSize, nil)
+	}
+
+	key := layoutKey{line: string(line), fontSize: fontSize}
+	if glyphs, ok := f.layouts[key]; ok {
+		return glyphs
+	}
+	glyphs := f.shapeLine(line, fontSize)
+	if f.layouts == nil || len(f.layouts) >= maxCachedLayouts {
+		f.layouts = make(map[layoutKey][]shapedGlyph)
+	}
+	f.layouts[key] = glyphs
+	return glyphs
+}
+
+// shapeLine shapes a single line of text and positions its glyphs.
+func (f *Font) shapeLine(line []rune, fontSize float32) []shapedGlyph {
+	s := f.shaper
+	input := shaping.Input{
+		Text:      line,
+		RunStart:  0,
+		RunEnd:    len(line),
+		Direction: di.DirectionLTR,
+		Face:      s.face,
+		Size:      fixed.Int26_6(fontSize * 64),
+	}
+	runs := s.segmenter.Split(input, singleFace{s.face})
+	outputs := make([]shaping.Output, len(runs))
+	for i, run := range runs {
+		outputs[i] = s.harfbuzz.Shape(run)
+	}
+
+	glyphs := make([]shapedGlyph, 0, len(line))
+	var penX float32
+	for _, i := range visualOrder(line, outputs) {
+		out := outputs[i]
+		rtl := out.Direction.Progression() == di.TowardTopLeft
+		for j := 0; j < len(out.Glyphs); {
+			g := out.Glyphs[j]
+			clusterEnd := min(j+max(g.GlyphCount, 1), len(out.Glyphs))
+			if f.atlasHasGlyphs(out.Glyphs[j:clusterEnd]) {
+				for _, cg := range out.Glyphs[j:clusterEnd] {
+					glyphs = append(glyphs, shapedGlyph{
+						glyph:   f.atlas.GetGlyphByIndex(int(cg.GlyphID)),
+						x:       penX + fixedToFloat(cg.XOffset),
+						y:       -fixedToFloat(cg.YOffset),
+						advance: fixedToFloat(cg.XAdvance),
+						cluster: cg.ClusterIndex,
+						rtl:     rtl,
+					})
+					penX += fixedToFloat(cg.XAdvance)
+				}
+				j = clusterEnd
+				continue
+			}
+
+			// The atlas does not hold this cluster, so draw it character by character
+			start := len(glyphs)
+			glyphs = f.layoutRunes(line, g.ClusterIndex, g.ClusterIndex+g.RuneCount, penX, fontSize, glyphs)
+			if rtl {
+				reverseGlyphs(glyphs[start:], penX)
+			}
+			for _, fg := range glyphs[start:] {
+				penX += fg.advance
+			}
+			j = clusterEnd
+		}
+	}
+	return glyphs
+}
+
+// atlasHasGlyphs reports whether every glyph of a shaped cluster is in this font's atlas.
+func (f *Font) atlasHasGlyphs(cluster []shaping.Glyph) bool {
+	for _, g := range cluster {
+		if g.GlyphID == 0 || f.atlas.GetGlyphByIndex(int(g.GlyphID)) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// layoutRunes appends line[start:end] to glyphs one character at a time,
+// starting at penX and searching the fallback chain for each character.
+func (f *Font) layoutRunes(line []rune, start, end int, penX, fontSize float32, glyphs []shapedGlyph) []shapedGlyph {
+	for i := start; i < end; i++ {
+		glyph, owner, slot := f.lookupGlyph(line[i])
+		advance := f.missingGlyphAdvance(fontSize)
+		if glyph != nil {
+			advance = float32(glyph.Quad.Advance) * owner.advanceScale(fontSize)
+		}
+		glyphs = append(glyphs, shapedGlyph{
+			glyph:   glyph,
+			slot:    slot,
+			x:       penX,
+			advance: advance,
+			cluster: i,
+		})
+		penX += advance
+	}
+	return glyphs
+}
+
+// reverseGlyphs lays out glyphs right to left from penX, for fallback clusters in right-to-left runs.
+func reverseGlyphs(glyphs []shapedGlyph, penX float32) {
+	for i, j := 0, len(glyphs)-1; i < j; i, j = i+1, j-1 {
+		glyphs[i], glyphs[j] = glyphs[j], glyphs[i]
+	}
+	for i := range glyphs {
+		glyphs[i].x = penX
+		glyphs[i].rtl = true
+		penX += glyphs[i].advance
+	}
+}
+
+// visualOrder returns the indices of runs in the order they appear from left to right.
+// Like the typesetting line wrapper, each sequence of right-to-left runs in the
+// left-to-right line is reversed as a block. Left-to-right runs without letters,
+// such as numbers, that follow right-to-left text belong to its block.
+func visualOrder(line []rune, runs []shaping.Output) []int {
+	order := make([]int, len(runs))
+	rtlStart := -1
+	for i, run := range runs {
+		order[i] = i
+		if run.Direction.Progression() == di.TowardTopLeft {
+			if rtlStart == -1 {
+				rtlStart = i
+			}
+			continue
+		}
+		if rtlStart != -1 && !hasLetter(line[run.Runes.Offset:run.Runes.Offset+run.Runes.Count]) {
+			continue
+		}
+		if rtlStart != -1 {
+			reverseInts(order[rtlStart:i])
+			rtlStart = -1
+		}
+	}
+	if rtlStart != -1 {
+		reverseInts(order[rtlStart:])
+	}
+	return order
+}
+
+func hasLetter(runes []rune) bool {
+	for _, r := range runes {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func reverseInts(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// lineWidth returns the total advance of a laid out line.
+func lineWidth(glyphs []shapedGlyph) float32 {
+	var width float32
+	for _, g := range glyphs {
+		width += g.advance
+	}
+	return width
+}
+
+func fixedToFloat(v fixed.Int26_6) float32 {
+	return float32(v) / 64
+}
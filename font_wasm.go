@@ -12,6 +12,7 @@ import (
 	"image/color"
 	_ "image/png"
 	"io"
+	"strings"
 
 	"github.com/dfirebaugh/hlg/graphics"
 )
@@ -61,6 +62,9 @@ type Font struct {
 	emSize      float64
 	atlasImage  image.Image
 	spaceGlyph  *graphics.GlyphInfo
+	fallbacks   []*Font
+	shaper      *textShaper
+	layouts     map[layoutKey][]shapedGlyph
 }
 
 // LoadFont is not supported in WASM builds - use LoadFontFromAtlasBytes instead
@@ -111,6 +115,7 @@ type AtlasMetadata struct {
 	} `json:"metrics"`
 	Glyphs []struct {
 		Unicode     int     `json:"unicode"`
+		Index       *int    `json:"index,omitempty"` // set when the atlas was generated by glyph index
 		Advance     float64 `json:"advance"`
 		PlaneBounds *struct {
 			Left   float64 `json:"left"`
@@ -195,7 +200,12 @@ func LoadFontFromAtlasBytes(atlasPNG, atlasJSON []byte) (*Font, error) {
 			}
 		}
 
-		atlas.AddGlyph(rune(g.Unicode), info)
+		if g.Index != nil {
+			atlas.AddGlyphByIndex(*g.Index, info)
+		}
+		if g.Unicode != 0 || g.Index == nil {
+			atlas.AddGlyph(rune(g.Unicode), info)
+		}
 	}
 
 	pixelRange := float64(meta.Atlas.DistanceRange)
@@ -276,52 +286,51 @@ func (f *Font) SaveAtlasDebug(filename string) error {
 
 func (f *Font) DrawText(dc graphics.GlyphDrawer, text string, x, y, fontSize float32, c color.Color) {
 	metrics := f.atlas.GetMetrics()
-	advanceScale := float32(float64(fontSize) / metrics.EmSize)
+	// Glyphs from fallback fonts need a drawer that can select an atlas slot.
+	// If dc cannot, they are drawn straight into the current frame batch.
+	atlasDC, ok := dc.(graphics.AtlasGlyphDrawer)
+	if !ok {
+		atlasDC = FrameGlyphDrawer{}
+	}
 
-	cursorX := x
 	capHeightScale := float32(metrics.Ascender / metrics.EmSize * 0.68)
-	cursorY := y + capHeightScale*fontSize
-
-	for _, ch := range text {
-		if ch == '\n' {
-			cursorX = x
-			cursorY += fontSize * 1.2
-			continue
-		}
+	baselineY := y + capHeightScale*fontSize
 
-		glyph := f.atlas.GetGlyph(ch)
-		if glyph == nil {
-			if f.spaceGlyph != nil {
-				cursorX += float32(f.spaceGlyph.Quad.Advance) * advanceScale
+	for lineIndex, line := range strings.Split(text, "\n") {
+		for _, sg := range f.layoutLine([]rune(line), fontSize) {
+			if sg.glyph == nil {
+				// Characters missing from the whole chain only take up space
+				continue
 			}
-			continue
-		}
 
-		q := glyph.Quad
+			q := sg.glyph.Quad
+			cursorX := x + sg.x
+			cursorY := baselineY + float32(lineIndex)*fontSize*1.2 + sg.y
 
-		x0 := cursorX + float32(q.PL)*fontSize
-		y0 := cursorY - float32(q.PT)*fontSize
-		x1 := cursorX + float32(q.PR)*fontSize
-		y1 := cursorY - float32(q.PB)*fontSize
+			x0 := cursorX + float32(q.PL)*fontSize
+			y0 := cursorY - float32(q.PT)*fontSize
+			x1 := cursorX + float32(q.PR)*fontSize
+			y1 := cursorY - float32(q.PB)*fontSize
 
-		u0 := float32(q.S0)
-		v0 := float32(q.T0)
-		u1 := float32(q.S1)
-		v1 := float32(q.T1)
+			u0 := float32(q.S0)
+			v0 := float32(q.T0)
+			u1 := float32(q.S1)
+			v1 := float32(q.T1)
 
-		dc.DrawGlyph(x0, y0, x1, y1, u0, v0, u1, v1, c)
-
-		cursorX += float32(q.Advance) * advanceScale
+			if sg.slot == 0 {
+				dc.DrawGlyph(x0, y0, x1, y1, u0, v0, u1, v1, c)
+			} else {
+				atlasDC.DrawAtlasGlyph(x0, y0, x1, y1, u0, v0, u1, v1, sg.slot, c)
+			}
+		}
 	}
 }
 
 func (f *Font) RenderText(text string, x, y, fontSize float32, c color.Color, screenWidth, screenHeight int) []graphics.PrimitiveVertex {
 	metrics := f.atlas.GetMetrics()
-	advanceScale := float32(float64(fontSize) / metrics.EmSize)
 
-	cursorX := x
 	capHeightScale := float32(metrics.Ascender / metrics.EmSize * 0.68)
-	cursorY := y + capHeightScale*fontSize
+	baselineY := y + capHeightScale*fontSize
 
 	vertices := make([]graphics.PrimitiveVertex, 0, len(text)*6)
 
@@ -336,48 +345,43 @@ func (f *Font) RenderText(text string, x, y, fontSize float32, c color.Color, sc
 		float32(a) / 0xffff,
 	}
 
-	for _, ch := range text {
-		if ch == '\n' {
-			cursorX = x
-			cursorY += fontSize * 1.2
-			continue
-		}
-
-		glyph := f.atlas.GetGlyph(ch)
-		if glyph == nil {
-			if f.spaceGlyph != nil {
-				cursorX += float32(f.spaceGlyph.Quad.Advance) * advanceScale
+	for lineIndex, line := range strings.Split(text, "\n") {
+		for _, sg := range f.layoutLine([]rune(line), fontSize) {
+			if sg.glyph == nil {
+				// Characters missing from the whole chain only take up space
+				continue
 			}
-			continue
-		}
 
-		q := glyph.Quad
-
-		x0 := cursorX + float32(q.PL)*fontSize
-		y0 := cursorY - float32(q.PT)*fontSize
-		x1 := cursorX + float32(q.PR)*fontSize
-		y1 := cursorY - float32(q.PB)*fontSize
-
-		u0 := float32(q.S0)
-		v0 := float32(q.T0)
-		u1 := float32(q.S1)
-		v1 := float32(q.T1)
-
-		ndcX0 := (x0/sw)*2.0 - 1.0
-		ndcY0 := 1.0 - (y0/sh)*2.0
-		ndcX1 := (x1/sw)*2.0 - 1.0
-		ndcY1 := 1.0 - (y1/sh)*2.0
-
-		vertices = append(vertices,
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u0, v0}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u0, v1}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u1, v0}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u0, v1}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u1, v1}},
-			graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, TexCoords: [2]float32{u1, v0}},
-		)
-
-		cursorX += float32(q.Advance) * advanceScale
+			q := sg.glyph.Quad
+			cursorX := x + sg.x
+			cursorY := baselineY + float32(lineIndex)*fontSize*1.2 + sg.y
+
+			x0 := cursorX + float32(q.PL)*fontSize
+			y0 := cursorY - float32(q.PT)*fontSize
+			x1 := cursorX + float32(q.PR)*fontSize
+			y1 := cursorY - float32(q.PB)*fontSize
+
+			u0 := float32(q.S0)
+			v0 := float32(q.T0)
+			u1 := float32(q.S1)
+			v1 := float32(q.T1)
+
+			atlasSlot := float32(sg.slot)
+
+			ndcX0 := (x0/sw)*2.0 - 1.0
+			ndcY0 := 1.0 - (y0/sh)*2.0
+			ndcX1 := (x1/sw)*2.0 - 1.0
+			ndcY1 := 1.0 - (y1/sh)*2.0
+
+			vertices = append(vertices,
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u0, v0}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u0, v1}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u1, v0}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX0, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u0, v1}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY1, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u1, v1}},
+				graphics.PrimitiveVertex{Position: [3]float32{ndcX1, ndcY0, 0.0}, LocalPosition: [2]float32{0, 0}, OpCode: graphics.OpCodeMSDF, Radius: 0.0, Color: colorVec, AtlasSlot: atlasSlot, TexCoords: [2]float32{u1, v0}},
+			)
+		}
 	}
 
 	return vertices
@@ -385,11 +389,9 @@ func (f *Font) RenderText(text string, x, y, fontSize float32, c color.Color, sc
 
 func (f *Font) RenderTextPrimitives(text string, x, y, fontSize float32, c color.Color) []graphics.Primitive {
 	metrics := f.atlas.GetMetrics()
-	advanceScale := float32(float64(fontSize) / metrics.EmSize)
 
-	cursorX := x
 	capHeightScale := float32(metrics.Ascender / metrics.EmSize * 0.68)
-	cursorY := y + capHeightScale*fontSize
+	baselineY := y + capHeightScale*fontSize
 
 	primitives := make([]graphics.Primitive, 0, len(text))
 
@@ -401,91 +403,55 @@ func (f *Font) RenderTextPrimitives(text string, x, y, fontSize float32, c color
 		float32(a) / 0xffff,
 	}
 
-	for _, ch := range text {
-		if ch == '\n' {
-			cursorX = x
-			cursorY += fontSize * 1.2
-			continue
-		}
-
-		glyph := f.atlas.GetGlyph(ch)
-		if glyph == nil {
-			if f.spaceGlyph != nil {
-				cursorX += float32(f.spaceGlyph.Quad.Advance) * advanceScale
+	for lineIndex, line := range strings.Split(text, "\n") {
+		for _, sg := range f.layoutLine([]rune(line), fontSize) {
+			if sg.glyph == nil {
+				// Characters missing from the whole chain only take up space
+				continue
 			}
-			continue
-		}
 
-		q := glyph.Quad
+			q := sg.glyph.Quad
+			cursorX := x + sg.x
+			cursorY := baselineY + float32(lineIndex)*fontSize*1.2 + sg.y
 
-		x0 := cursorX + float32(q.PL)*fontSize
-		y0 := cursorY - float32(q.PT)*fontSize
-		x1 := cursorX + float32(q.PR)*fontSize
-		y1 := cursorY - float32(q.PB)*fontSize
+			x0 := cursorX + float32(q.PL)*fontSize
+			y0 := cursorY - float32(q.PT)*fontSize
+			x1 := cursorX + float32(q.PR)*fontSize
+			y1 := cursorY - float32(q.PB)*fontSize
 
-		u0 := float32(q.S0)
-		v0 := float32(q.T0)
-		u1 := float32(q.S1)
-		v1 := float32(q.T1)
+			u0 := float32(q.S0)
+			v0 := float32(q.T0)
+			u1 := float32(q.S1)
+			v1 := float32(q.T1)
 
-		w := x1 - x0
-		h := y1 - y0
-		if w <= 0 || h <= 0 {
-			cursorX += float32(q.Advance) * advanceScale
-			continue
-		}
+			w := x1 - x0
+			h := y1 - y0
+			if w <= 0 || h <= 0 {
+				continue
+			}
 
-		primitives = append(primitives, graphics.Primitive{
-			X:      x0,
-			Y:      y0,
-			W:      w,
-			H:      h,
-			Color:  colorVec,
-			Radius: 0,
-			OpCode: graphics.OpCodeMSDF,
-			Extra:  [4]float32{u0, v0, u1 - u0, v1 - v0},
-		})
-
-		cursorX += float32(q.Advance) * advanceScale
+			primitives = append(primitives, graphics.Primitive{
+				X:         x0,
+				Y:         y0,
+				W:         w,
+				H:         h,
+				Color:     colorVec,
+				Radius:    0,
+				OpCode:    graphics.OpCodeMSDF,
+				AtlasSlot: float32(sg.slot),
+				Extra:     [4]float32{u0, v0, u1 - u0, v1 - v0},
+			})
+		}
 	}
 
 	return primitives
 }
 
-func (f *Font) SetAsActiveAtlas() {
-	SetMSDFAtlas(f.atlasImage, f.config.PixelRange)
-}
-
 func (f *Font) MeasureText(text string, fontSize float32) float32 {
-	metrics := f.atlas.GetMetrics()
-	advanceScale := float32(float64(fontSize) / metrics.EmSize)
-
 	var maxWidth float32
-	var currentWidth float32
-	for _, ch := range text {
-		if ch == '\n' {
-			if currentWidth > maxWidth {
-				maxWidth = currentWidth
-			}
-			currentWidth = 0
-			continue
-		}
-
-		glyph := f.atlas.GetGlyph(ch)
-		if glyph == nil {
-			if f.spaceGlyph != nil {
-				currentWidth += float32(f.spaceGlyph.Quad.Advance) * advanceScale
-			}
-			continue
-		}
-
-		currentWidth += float32(glyph.Quad.Advance) * advanceScale
-	}
-
-	if currentWidth > maxWidth {
-		maxWidth = currentWidth
+	for _, line := range strings.Split(text, "\n") {
+		maxWidth = max(maxWidth, lineWidth(f.layoutLine([]rune(line), fontSize)))
 	}
-
 	return maxWidth
 }
 
@@ -4,6 +4,7 @@ go 1.25.5
 
 require (
 	github.com/dfirebaugh/msdf v0.0.1
+	github.com/go-text/typesetting v0.2.1
 	github.com/llgcode/draw2d v0.0.0-20260207101443-2bac6a2fac03
 	golang.org/x/image v0.36.0
 	tinygo.org/x/tinyfont v0.6.0
@@ -12,6 +13,7 @@ require (
 require (
 	github.com/ebitengine/purego v0.9.1 // indirect
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
+	golang.org/x/text v0.34.0 // indirect
 )
 
 require (
@@ -27,6 +27,10 @@ type Canvas struct {
 	aspectRatio               float64 // targetWidth / targetHeight
 	hasTargetSize             bool    // true if SetWindowSize was called
 
+	clipboard  string // text last pasted or copied
+	cursor     string // CSS cursor shown in CursorNormal mode
+	cursorMode input.CursorMode
+
 	resizedCallback func(physicalWidth, physicalHeight uint32)
 	inputCallback   func(eventChan chan input.Event)
 	closeCallback   func()
@@ -77,6 +81,9 @@ func NewCanvas(canvasID string, width, height int) (*Canvas, error) {
 
 	canvas.setupEventListeners()
 	canvas.setupResizeListener()
+	canvas.setupClipboardListener()
+	canvas.setupDropListener()
+	canvas.setupWindowStateListeners()
 
 	return canvas, nil
 }
@@ -0,0 +1,40 @@
+//go:build js && wasm
+
+package canvas
+
+import "syscall/js"
+
+// ignoreRejection handles promises the browser rejects, such as clipboard writes
+// without focus and fullscreen requests outside of input handling.
+var ignoreRejection = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+	return nil
+})
+
+// setupClipboardListener keeps the text of paste events, since the async Clipboard API
+// cannot be waited on from a frame. A paste event follows the ctrl+v key press within
+// the same browser task, so the frame handling the key press sees the pasted text.
+func (c *Canvas) setupClipboardListener() {
+	js.Global().Get("document").Call("addEventListener", "paste", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		data := args[0].Get("clipboardData")
+		if data.Truthy() {
+			c.clipboard = data.Call("getData", "text").String()
+		}
+		return nil
+	}))
+}
+
+// GetClipboardText returns the text last pasted into the page or copied by the program
+func (c *Canvas) GetClipboardText() string {
+	return c.clipboard
+}
+
+// SetClipboardText puts text on the system clipboard through the async Clipboard API,
+// where the browser allows it, and keeps it for GetClipboardText
+func (c *Canvas) SetClipboardText(text string) {
+	c.clipboard = text
+	clipboard := js.Global().Get("navigator").Get("clipboard")
+	if clipboard.Truthy() {
+		// The text is kept for GetClipboardText even if the browser refuses it
+		clipboard.Call("writeText", text).Call("catch", ignoreRejection)
+	}
+}
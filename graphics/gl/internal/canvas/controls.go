@@ -0,0 +1,89 @@
+//go:build js && wasm
+
+package canvas
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"syscall/js"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// SetWindowIcon sets the page's favicon to the largest of the images
+func (c *Canvas) SetWindowIcon(images []image.Image) {
+	var icon image.Image
+	for _, img := range images {
+		if icon == nil || img.Bounds().Dx() > icon.Bounds().Dx() {
+			icon = img
+		}
+	}
+	if icon == nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, icon); err != nil {
+		return
+	}
+
+	doc := js.Global().Get("document")
+	link := doc.Call("querySelector", "link[rel~='icon']")
+	if !link.Truthy() {
+		link = doc.Call("createElement", "link")
+		link.Set("rel", "icon")
+		doc.Get("head").Call("appendChild", link)
+	}
+	link.Set("href", "data:image/png;base64,"+base64.StdEncoding.EncodeToString(buf.Bytes()))
+}
+
+// SetWindowPosition is a no-op for web
+func (c *Canvas) SetWindowPosition(x, y int) {}
+
+// SetWindowSizeLimits is a no-op for web (CSS controls this)
+func (c *Canvas) SetWindowSizeLimits(minWidth, minHeight, maxWidth, maxHeight int) {}
+
+// SetAlwaysOnTop is a no-op for web
+func (c *Canvas) SetAlwaysOnTop(v bool) {}
+
+// MinimizeWindow is a no-op for web
+func (c *Canvas) MinimizeWindow() {}
+
+// MaximizeWindow is a no-op for web
+func (c *Canvas) MaximizeWindow() {}
+
+// RestoreWindow is a no-op for web
+func (c *Canvas) RestoreWindow() {}
+
+// RequestAttention is a no-op for web
+func (c *Canvas) RequestAttention() {}
+
+// setupWindowStateListeners sends focus events when the page gains or loses focus, and
+// minimize and restore events when it is hidden, as in a background tab, or shown.
+func (c *Canvas) setupWindowStateListeners() {
+	send := func(eventType input.EventType) {
+		c.eventChan <- input.Event{Type: eventType}
+		if c.inputCallback != nil {
+			c.inputCallback(c.eventChan)
+		}
+	}
+	window := js.Global().Get("window")
+	window.Call("addEventListener", "focus", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		send(input.WindowFocused)
+		return nil
+	}))
+	window.Call("addEventListener", "blur", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		send(input.WindowUnfocused)
+		return nil
+	}))
+	doc := js.Global().Get("document")
+	doc.Call("addEventListener", "visibilitychange", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if doc.Get("hidden").Bool() {
+			send(input.WindowMinimized)
+		} else {
+			send(input.WindowRestored)
+		}
+		return nil
+	}))
+}
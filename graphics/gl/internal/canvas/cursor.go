@@ -0,0 +1,69 @@
+//go:build js && wasm
+
+package canvas
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"syscall/js"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+var cursorCSS = map[input.CursorShape]string{
+	input.CursorArrow:      "default",
+	input.CursorIBeam:      "text",
+	input.CursorCrosshair:  "crosshair",
+	input.CursorHand:       "pointer",
+	input.CursorResizeH:    "ew-resize",
+	input.CursorResizeV:    "ns-resize",
+	input.CursorResizeNWSE: "nwse-resize",
+}
+
+// SetCursorShape shows a standard cursor over the canvas
+func (c *Canvas) SetCursorShape(shape input.CursorShape) {
+	css, ok := cursorCSS[shape]
+	if !ok {
+		css = "default"
+	}
+	c.cursor = css
+	c.applyCursor()
+}
+
+// SetCursorImage shows img as the cursor over the canvas, with its hot spot at
+// (hotX, hotY) in the image
+func (c *Canvas) SetCursorImage(img image.Image, hotX, hotY int) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return
+	}
+	url := "data:image/png;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	c.cursor = fmt.Sprintf("url(%s) %d %d, auto", url, hotX, hotY)
+	c.applyCursor()
+}
+
+// SetCursorMode shows, hides or locks the cursor. Locking uses the Pointer Lock API,
+// which browsers only grant while handling a click or key press.
+func (c *Canvas) SetCursorMode(mode input.CursorMode) {
+	c.cursorMode = mode
+	doc := js.Global().Get("document")
+	if mode == input.CursorLocked {
+		c.element.Call("requestPointerLock")
+	} else if doc.Get("pointerLockElement").Equal(c.element) {
+		doc.Call("exitPointerLock")
+	}
+	c.applyCursor()
+}
+
+func (c *Canvas) applyCursor() {
+	css := c.cursor
+	if c.cursorMode != input.CursorNormal {
+		css = "none"
+	} else if css == "" {
+		css = "default"
+	}
+	c.element.Get("style").Set("cursor", css)
+}
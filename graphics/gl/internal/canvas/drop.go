@@ -0,0 +1,67 @@
+//go:build js && wasm
+
+package canvas
+
+import (
+	"syscall/js"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// setupDropListener delivers files dropped onto the canvas, with their contents, as a
+// FilesDropped event once the browser has read them all.
+func (c *Canvas) setupDropListener() {
+	// The page only accepts drops when dragover is canceled
+	c.element.Call("addEventListener", "dragover", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		args[0].Call("preventDefault")
+		return nil
+	}))
+
+	c.element.Call("addEventListener", "drop", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		event := args[0]
+		// Keep the browser from opening the file in place of the page
+		event.Call("preventDefault")
+		list := event.Get("dataTransfer").Get("files")
+		count := list.Get("length").Int()
+		if count == 0 {
+			return nil
+		}
+
+		files := make([]input.DroppedFile, count)
+		pending := count
+		done := func() {
+			pending--
+			if pending > 0 {
+				return
+			}
+			c.eventChan <- input.Event{Type: input.FilesDropped, Files: files}
+			if c.inputCallback != nil {
+				c.inputCallback(c.eventChan)
+			}
+		}
+		for i := 0; i < count; i++ {
+			file := list.Call("item", i)
+			files[i].Name = file.Get("name").String()
+
+			var onRead, onError js.Func
+			onRead = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+				data := js.Global().Get("Uint8Array").New(args[0])
+				files[i].Data = make([]byte, data.Get("length").Int())
+				js.CopyBytesToGo(files[i].Data, data)
+				onRead.Release()
+				onError.Release()
+				done()
+				return nil
+			})
+			// Unreadable files are delivered without contents
+			onError = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+				onRead.Release()
+				onError.Release()
+				done()
+				return nil
+			})
+			file.Call("arrayBuffer").Call("then", onRead, onError)
+		}
+		return nil
+	}))
+}
@@ -17,6 +17,7 @@ func (c *Canvas) setupEventListeners() {
 		c.eventChan <- input.Event{
 			Type:        input.MousePress,
 			MouseButton: input.MouseButton(button),
+			Mods:        eventMods(event),
 		}
 		if c.inputCallback != nil {
 			c.inputCallback(c.eventChan)
@@ -30,6 +31,7 @@ func (c *Canvas) setupEventListeners() {
 		c.eventChan <- input.Event{
 			Type:        input.MouseRelease,
 			MouseButton: input.MouseButton(button),
+			Mods:        eventMods(event),
 		}
 		if c.inputCallback != nil {
 			c.inputCallback(c.eventChan)
@@ -61,6 +63,8 @@ func (c *Canvas) setupEventListeners() {
 			Type: input.MouseMove,
 			X:    x,
 			Y:    y,
+			DX:   event.Get("movementX").Float(),
+			DY:   event.Get("movementY").Float(),
 		}
 		if c.inputCallback != nil {
 			c.inputCallback(c.eventChan)
@@ -68,6 +72,39 @@ func (c *Canvas) setupEventListeners() {
 		return nil
 	}))
 
+	// Wheel events, normalized to about one unit per wheel notch as GLFW reports them.
+	// Not passive, so scrolling over the canvas does not also scroll the page.
+	c.element.Call("addEventListener", "wheel", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		event := args[0]
+		event.Call("preventDefault")
+		scale := 1.0
+		switch event.Get("deltaMode").Int() {
+		case 0: // pixels
+			scale = 1.0 / 100
+		case 1: // lines
+			scale = 1.0 / 3
+		}
+		// Browsers report positive deltas when scrolling down and right; like GLFW, hlg
+		// scrolls up and left on positive offsets
+		c.eventChan <- input.Event{
+			Type:    input.MouseScroll,
+			ScrollX: -event.Get("deltaX").Float() * scale,
+			ScrollY: -event.Get("deltaY").Float() * scale,
+		}
+		if c.inputCallback != nil {
+			c.inputCallback(c.eventChan)
+		}
+		return nil
+	}), map[string]interface{}{"passive": false})
+
+	// Touch events. Not passive, so the browser neither scrolls nor zooms the page nor
+	// synthesizes mouse events from them; hlg emulates the mouse itself.
+	touchOpts := map[string]interface{}{"passive": false}
+	c.element.Call("addEventListener", "touchstart", c.touchListener(input.TouchStart), touchOpts)
+	c.element.Call("addEventListener", "touchmove", c.touchListener(input.TouchMove), touchOpts)
+	c.element.Call("addEventListener", "touchend", c.touchListener(input.TouchEnd), touchOpts)
+	c.element.Call("addEventListener", "touchcancel", c.touchListener(input.TouchCancel), touchOpts)
+
 	// Keyboard events (on document for global capture)
 	doc := js.Global().Get("document")
 
@@ -75,8 +112,10 @@ func (c *Canvas) setupEventListeners() {
 		event := args[0]
 		keyCode := event.Get("keyCode").Int()
 		c.eventChan <- input.Event{
-			Type: input.KeyPress,
-			Key:  convertKeyCode(keyCode),
+			Type:   input.KeyPress,
+			Key:    convertKeyCode(keyCode),
+			Mods:   eventMods(event),
+			Repeat: event.Get("repeat").Bool(),
 		}
 		if c.inputCallback != nil {
 			c.inputCallback(c.eventChan)
@@ -90,6 +129,7 @@ func (c *Canvas) setupEventListeners() {
 		c.eventChan <- input.Event{
 			Type: input.KeyRelease,
 			Key:  convertKeyCode(keyCode),
+			Mods: eventMods(event),
 		}
 		if c.inputCallback != nil {
 			c.inputCallback(c.eventChan)
@@ -114,6 +154,39 @@ func (c *Canvas) setupEventListeners() {
 	}))
 }
 
+// touchListener returns a listener sending an event of the given type for each touch
+// changed by a DOM touch event
+func (c *Canvas) touchListener(eventType input.EventType) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		event := args[0]
+		event.Call("preventDefault")
+		rect := c.element.Call("getBoundingClientRect")
+		rectLeft := rect.Get("left").Float()
+		rectTop := rect.Get("top").Float()
+		rectWidth := rect.Get("width").Float()
+		rectHeight := rect.Get("height").Float()
+
+		touches := event.Get("changedTouches")
+		for i := 0; i < touches.Get("length").Int(); i++ {
+			touch := touches.Call("item", i)
+			relX := touch.Get("clientX").Float() - rectLeft
+			relY := touch.Get("clientY").Float() - rectTop
+			x, y := c.cssToLogicalWithRect(relX, relY, rectWidth, rectHeight)
+
+			c.eventChan <- input.Event{
+				Type:    eventType,
+				TouchID: touch.Get("identifier").Int(),
+				X:       x,
+				Y:       y,
+			}
+			if c.inputCallback != nil {
+				c.inputCallback(c.eventChan)
+			}
+		}
+		return nil
+	})
+}
+
 // setupResizeListener sets up window resize listener
 func (c *Canvas) setupResizeListener() {
 	// Listen for window resize events to re-fit the canvas
@@ -235,3 +308,27 @@ func convertKeyCode(keyCode int) input.Key {
 		return input.Key(keyCode)
 	}
 }
+
+// eventMods returns the modifier keys held during a keyboard or mouse event.
+func eventMods(event js.Value) input.ModifierKey {
+	var mods input.ModifierKey
+	if event.Get("shiftKey").Bool() {
+		mods |= input.ModShift
+	}
+	if event.Get("ctrlKey").Bool() {
+		mods |= input.ModControl
+	}
+	if event.Get("altKey").Bool() {
+		mods |= input.ModAlt
+	}
+	if event.Get("metaKey").Bool() {
+		mods |= input.ModSuper
+	}
+	if event.Call("getModifierState", "CapsLock").Bool() {
+		mods |= input.ModCapsLock
+	}
+	if event.Call("getModifierState", "NumLock").Bool() {
+		mods |= input.ModNumLock
+	}
+	return mods
+}
@@ -0,0 +1,49 @@
+//go:build js && wasm
+
+package canvas
+
+import (
+	"syscall/js"
+
+	"github.com/dfirebaugh/hlg/graphics"
+)
+
+// Monitors returns the screen the page is shown on. Browsers do not report its refresh
+// rate or other video modes.
+func (c *Canvas) Monitors() []graphics.Monitor {
+	screen := js.Global().Get("screen")
+	width, height := screen.Get("width").Int(), screen.Get("height").Int()
+	dpr := float32(js.Global().Get("devicePixelRatio").Float())
+	return []graphics.Monitor{{
+		Name:    "screen",
+		Width:   width,
+		Height:  height,
+		ScaleX:  dpr,
+		ScaleY:  dpr,
+		Primary: true,
+		Modes:   []graphics.VideoMode{{Width: width, Height: height}},
+	}}
+}
+
+// SetFullscreen makes the canvas fill the screen with the Fullscreen API, which
+// browsers only allow while handling a click or key press. The monitor and mode are
+// ignored; the browser picks them.
+func (c *Canvas) SetFullscreen(monitor int, mode graphics.VideoMode) {
+	if c.IsFullscreen() {
+		return
+	}
+	// Rejected requests, made outside of input handling, leave the page as it was
+	c.element.Call("requestFullscreen").Call("catch", ignoreRejection)
+}
+
+// SetWindowed leaves fullscreen. The canvas is fitted to the page again on resize.
+func (c *Canvas) SetWindowed() {
+	if c.IsFullscreen() {
+		js.Global().Get("document").Call("exitFullscreen").Call("catch", ignoreRejection)
+	}
+}
+
+// IsFullscreen returns true if the canvas fills the screen
+func (c *Canvas) IsFullscreen() bool {
+	return js.Global().Get("document").Get("fullscreenElement").Truthy()
+}
@@ -162,7 +162,7 @@ func (c *Context) Uniform2fv(location UniformLocation, data []float32) {
 }
 
 func (c *Context) Uniform4fv(location UniformLocation, data []float32) {
-	gl.Uniform4fv(int32(location), 1, &data[0])
+	gl.Uniform4fv(int32(location), int32(len(data)/4), &data[0])
 }
 
 func (c *Context) UniformMatrix4fv(location UniformLocation, transpose bool, data []float32) {
@@ -6,11 +6,16 @@ import (
 	"github.com/dfirebaugh/hlg/graphics"
 )
 
+// primitiveVertexSize is the size in bytes of a PrimitiveVertex in the vertex buffer.
+// Position[3], LocalPosition[2], OpCode, Radius, Color[4], TexCoords[2], HalfSize[2], AtlasSlot
+// Total: 16 floats = 64 bytes
+const primitiveVertexSize = 64
+
 // verticesToBytes converts PrimitiveVertex slice to bytes
 func verticesToBytes(vertices []graphics.PrimitiveVertex) []byte {
-	bytes := make([]byte, len(vertices)*60)
+	bytes := make([]byte, len(vertices)*primitiveVertexSize)
 	for i, v := range vertices {
-		offset := i * 60
+		offset := i * primitiveVertexSize
 		// Position
 		writeFloat32(bytes[offset:], v.Position[0])
 		writeFloat32(bytes[offset+4:], v.Position[1])
@@ -33,6 +38,8 @@ func verticesToBytes(vertices []graphics.PrimitiveVertex) []byte {
 		// HalfSize
 		writeFloat32(bytes[offset+52:], v.HalfSize[0])
 		writeFloat32(bytes[offset+56:], v.HalfSize[1])
+		// AtlasSlot
+		writeFloat32(bytes[offset+60:], v.AtlasSlot)
 	}
 	return bytes
 }
@@ -121,6 +128,7 @@ func convertPrimitivesToVertices(primitives []graphics.Primitive, screenW, scree
 				Color:         prim.Color,
 				TexCoords:     texCoords[texCoordIndices[i]],
 				HalfSize:      halfSize,
+				AtlasSlot:     prim.AtlasSlot,
 			})
 		}
 	}
@@ -10,6 +10,7 @@ import (
 // MSDFAtlas represents an MSDF font atlas
 type MSDFAtlas struct {
 	glyphs        map[rune]*graphics.GlyphInfo
+	glyphsByIndex map[int]*graphics.GlyphInfo
 	metrics       graphics.FontMetrics
 	distanceRange float64
 	isDisposed    bool
@@ -18,6 +19,7 @@ type MSDFAtlas struct {
 func NewMSDFAtlas(atlasImg image.Image, distanceRange float64) (*MSDFAtlas, error) {
 	return &MSDFAtlas{
 		glyphs:        make(map[rune]*graphics.GlyphInfo),
+		glyphsByIndex: make(map[int]*graphics.GlyphInfo),
 		distanceRange: distanceRange,
 	}, nil
 }
@@ -30,6 +32,14 @@ func (a *MSDFAtlas) GetGlyph(r rune) *graphics.GlyphInfo {
 	return a.glyphs[r]
 }
 
+func (a *MSDFAtlas) AddGlyphByIndex(index int, info *graphics.GlyphInfo) {
+	a.glyphsByIndex[index] = info
+}
+
+func (a *MSDFAtlas) GetGlyphByIndex(index int) *graphics.GlyphInfo {
+	return a.glyphsByIndex[index]
+}
+
 func (a *MSDFAtlas) SetMetrics(metrics graphics.FontMetrics) {
 	a.metrics = metrics
 }
@@ -41,6 +51,7 @@ func (a *MSDFAtlas) GetMetrics() graphics.FontMetrics {
 func (a *MSDFAtlas) Dispose() {
 	a.isDisposed = true
 	a.glyphs = nil
+	a.glyphsByIndex = nil
 }
 
 func (a *MSDFAtlas) IsDisposed() bool {
@@ -5,6 +5,7 @@ package pipelines
 import (
 	"image"
 	"image/draw"
+	"strconv"
 
 	"github.com/dfirebaugh/hlg/graphics"
 	"github.com/dfirebaugh/hlg/graphics/gl/internal/glapi"
@@ -25,9 +26,12 @@ type PrimitiveBuffer struct {
 	screenWidth  int
 	screenHeight int
 
-	// MSDF atlas resources
-	msdfTextureID glapi.Texture
-	msdfParams    [4]float32
+	// MSDF atlas resources, one texture per atlas slot
+	msdfTextureIDs [graphics.MaxMSDFAtlasSlots]glapi.Texture
+	msdfParams     [graphics.MaxMSDFAtlasSlots * 4]float32 // per slot: px_range, tex_width, tex_height, msdf_mode
+
+	// Text style table, three vec4 per style: outline color, glow color, params
+	textStyles [graphics.MaxTextStyles * 12]float32
 
 	// Cached canvas size for scissor calculations
 	cachedFBWidth  int
@@ -45,13 +49,18 @@ func NewPrimitiveBuffer(ctx *glapi.Context, surface Surface, sm *shader.ShaderMa
 		surface:       surface,
 		shaderManager: sm,
 		verticesCap:   1024 * 6,
-		msdfParams:    [4]float32{4.0, 1.0, 1.0, 0.0},
 		screenWidth:   sw,
 		screenHeight:  sh,
 	}
 
+	for slot := range graphics.MaxMSDFAtlasSlots {
+		p.msdfParams[slot*4+0] = 4.0
+		p.msdfParams[slot*4+1] = 1.0
+		p.msdfParams[slot*4+2] = 1.0
+	}
+
 	p.createVertexBuffer()
-	p.createMSDFTexture()
+	p.createMSDFTextures()
 
 	return p
 }
@@ -63,10 +72,10 @@ func (p *PrimitiveBuffer) createVertexBuffer() {
 	p.vbo = p.ctx.CreateBuffer()
 	p.ctx.BindBuffer(glapi.ARRAY_BUFFER, p.vbo)
 
-	stride := 60
+	stride := primitiveVertexSize
 
 	// Allocate buffer with initial capacity
-	p.ctx.BufferDataSize(glapi.ARRAY_BUFFER, p.verticesCap*60, glapi.DYNAMIC_DRAW)
+	p.ctx.BufferDataSize(glapi.ARRAY_BUFFER, p.verticesCap*primitiveVertexSize, glapi.DYNAMIC_DRAW)
 
 	// Position (location 0)
 	p.ctx.VertexAttribPointer(0, 3, glapi.FLOAT, false, stride, 0)
@@ -89,24 +98,38 @@ func (p *PrimitiveBuffer) createVertexBuffer() {
 	// HalfSize (location 6)
 	p.ctx.VertexAttribPointer(6, 2, glapi.FLOAT, false, stride, 52)
 	p.ctx.EnableVertexAttribArray(6)
+	// AtlasSlot (location 7)
+	p.ctx.VertexAttribPointer(7, 1, glapi.FLOAT, false, stride, 60)
+	p.ctx.EnableVertexAttribArray(7)
 
 	p.ctx.UnbindVertexArray()
 }
 
-func (p *PrimitiveBuffer) createMSDFTexture() {
-	p.msdfTextureID = p.ctx.CreateTexture()
-	p.ctx.BindTexture(glapi.TEXTURE_2D, p.msdfTextureID)
-	p.ctx.TexParameteri(glapi.TEXTURE_2D, glapi.TEXTURE_MIN_FILTER, glapi.LINEAR)
-	p.ctx.TexParameteri(glapi.TEXTURE_2D, glapi.TEXTURE_MAG_FILTER, glapi.LINEAR)
-	p.ctx.TexParameteri(glapi.TEXTURE_2D, glapi.TEXTURE_WRAP_S, glapi.CLAMP_TO_EDGE)
-	p.ctx.TexParameteri(glapi.TEXTURE_2D, glapi.TEXTURE_WRAP_T, glapi.CLAMP_TO_EDGE)
-
+func (p *PrimitiveBuffer) createMSDFTextures() {
+	// Create a 1x1 placeholder texture for every atlas slot
 	placeholder := []byte{0, 0, 0, 0}
-	p.ctx.TexImage2D(glapi.TEXTURE_2D, 0, glapi.RGBA, 1, 1, 0, glapi.RGBA, glapi.UNSIGNED_BYTE, placeholder)
+	for slot := range p.msdfTextureIDs {
+		p.msdfTextureIDs[slot] = p.ctx.CreateTexture()
+		p.ctx.BindTexture(glapi.TEXTURE_2D, p.msdfTextureIDs[slot])
+		p.ctx.TexParameteri(glapi.TEXTURE_2D, glapi.TEXTURE_MIN_FILTER, glapi.LINEAR)
+		p.ctx.TexParameteri(glapi.TEXTURE_2D, glapi.TEXTURE_MAG_FILTER, glapi.LINEAR)
+		p.ctx.TexParameteri(glapi.TEXTURE_2D, glapi.TEXTURE_WRAP_S, glapi.CLAMP_TO_EDGE)
+		p.ctx.TexParameteri(glapi.TEXTURE_2D, glapi.TEXTURE_WRAP_T, glapi.CLAMP_TO_EDGE)
+		p.ctx.TexImage2D(glapi.TEXTURE_2D, 0, glapi.RGBA, 1, 1, 0, glapi.RGBA, glapi.UNSIGNED_BYTE, placeholder)
+	}
 }
 
 // SetMSDFAtlas sets the MSDF atlas texture
 func (p *PrimitiveBuffer) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
+	p.SetMSDFAtlasSlot(0, atlasImg, pxRange)
+}
+
+// SetMSDFAtlasSlot loads an MSDF atlas into the given atlas slot
+func (p *PrimitiveBuffer) SetMSDFAtlasSlot(slot int, atlasImg image.Image, pxRange float64) {
+	if slot < 0 || slot >= graphics.MaxMSDFAtlasSlots {
+		return
+	}
+
 	r := atlasImg.Bounds()
 	width := r.Dx()
 	height := r.Dy()
@@ -117,17 +140,35 @@ func (p *PrimitiveBuffer) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
 		draw.Draw(rgbaImg, r, atlasImg, image.Point{}, draw.Over)
 	}
 
-	p.ctx.BindTexture(glapi.TEXTURE_2D, p.msdfTextureID)
+	p.ctx.BindTexture(glapi.TEXTURE_2D, p.msdfTextureIDs[slot])
 	p.ctx.TexImage2D(glapi.TEXTURE_2D, 0, glapi.RGBA, width, height, 0, glapi.RGBA, glapi.UNSIGNED_BYTE, rgbaImg.Pix)
 
-	p.msdfParams[0] = float32(pxRange)
-	p.msdfParams[1] = float32(width)
-	p.msdfParams[2] = float32(height)
+	p.msdfParams[slot*4+0] = float32(pxRange)
+	p.msdfParams[slot*4+1] = float32(width)
+	p.msdfParams[slot*4+2] = float32(height)
 }
 
 // SetMSDFMode sets the MSDF rendering mode
 func (p *PrimitiveBuffer) SetMSDFMode(mode int) {
-	p.msdfParams[3] = float32(mode)
+	for slot := range graphics.MaxMSDFAtlasSlots {
+		p.msdfParams[slot*4+3] = float32(mode)
+	}
+}
+
+// SetTextStyles loads the text style table. Style 0 is plain text and is left zero.
+func (p *PrimitiveBuffer) SetTextStyles(styles []graphics.TextStyleParams) {
+	for i := 1; i < graphics.MaxTextStyles; i++ {
+		var style graphics.TextStyleParams
+		if i < len(styles) {
+			style = styles[i]
+		}
+		base := i * 12
+		copy(p.textStyles[base:base+4], style.OutlineColor[:])
+		copy(p.textStyles[base+4:base+8], style.GlowColor[:])
+		p.textStyles[base+8] = style.OutlineWidth
+		p.textStyles[base+9] = style.GlowRadius
+		p.textStyles[base+10] = style.Softness
+	}
 }
 
 // EnableSnapMSDFToPixels enables pixel snapping for MSDF
@@ -213,7 +254,7 @@ func (p *PrimitiveBuffer) updateVertexBufferInternal(vertices []graphics.Primiti
 	}
 
 	// Orphan: signal driver we don't need old data
-	p.ctx.BufferDataSize(glapi.ARRAY_BUFFER, p.verticesCap*60, glapi.DYNAMIC_DRAW)
+	p.ctx.BufferDataSize(glapi.ARRAY_BUFFER, p.verticesCap*primitiveVertexSize, glapi.DYNAMIC_DRAW)
 
 	// Upload vertex data
 	data := verticesToBytes(p.vertices)
@@ -261,11 +302,17 @@ func (p *PrimitiveBuffer) GLRender() {
 	msdfParamsLoc := p.ctx.GetUniformLocation(program, "u_msdf_params")
 	p.ctx.Uniform4fv(msdfParamsLoc, p.msdfParams[:])
 
-	msdfAtlasLoc := p.ctx.GetUniformLocation(program, "u_msdf_atlas")
-	p.ctx.Uniform1i(msdfAtlasLoc, 0)
+	textStylesLoc := p.ctx.GetUniformLocation(program, "u_text_styles")
+	p.ctx.Uniform4fv(textStylesLoc, p.textStyles[:])
+
+	for slot, textureID := range p.msdfTextureIDs {
+		msdfAtlasLoc := p.ctx.GetUniformLocation(program, "u_msdf_atlases["+strconv.Itoa(slot)+"]")
+		p.ctx.Uniform1i(msdfAtlasLoc, slot)
 
+		p.ctx.ActiveTexture(glapi.TEXTURE0 + uint32(slot))
+		p.ctx.BindTexture(glapi.TEXTURE_2D, textureID)
+	}
 	p.ctx.ActiveTexture(glapi.TEXTURE0)
-	p.ctx.BindTexture(glapi.TEXTURE_2D, p.msdfTextureID)
 
 	p.ctx.BindVertexArray(p.vao)
 
@@ -338,7 +385,9 @@ func (p *PrimitiveBuffer) Dispose() {
 
 	p.ctx.DeleteVertexArray(p.vao)
 	p.ctx.DeleteBuffer(p.vbo)
-	p.ctx.DeleteTexture(p.msdfTextureID)
+	for _, textureID := range p.msdfTextureIDs {
+		p.ctx.DeleteTexture(textureID)
+	}
 }
 
 // IsDisposed returns whether the buffer has been disposed
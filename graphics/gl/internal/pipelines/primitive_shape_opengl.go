@@ -88,8 +88,8 @@ func (p *PrimitiveShape) createVertexBuffer() {
 	data := verticesToBytes(p.vertices)
 	p.ctx.BufferData(glapi.ARRAY_BUFFER, data, glapi.DYNAMIC_DRAW)
 
-	// PrimitiveVertex layout: Position[3], LocalPosition[2], OpCode, Radius, Color[4], TexCoords[2], HalfSize[2]
-	stride := 60
+	// PrimitiveVertex layout: Position[3], LocalPosition[2], OpCode, Radius, Color[4], TexCoords[2], HalfSize[2], AtlasSlot
+	stride := primitiveVertexSize
 
 	// Position (location 0)
 	p.ctx.VertexAttribPointer(0, 3, glapi.FLOAT, false, stride, 0)
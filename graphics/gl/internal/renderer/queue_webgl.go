@@ -374,11 +374,21 @@ func (rq *RenderQueue) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
 	rq.primitiveBuffer.SetMSDFAtlas(atlasImg, pxRange)
 }
 
+// SetMSDFAtlasSlot sets the MSDF atlas texture for an atlas slot
+func (rq *RenderQueue) SetMSDFAtlasSlot(slot int, atlasImg image.Image, pxRange float64) {
+	rq.primitiveBuffer.SetMSDFAtlasSlot(slot, atlasImg, pxRange)
+}
+
 // SetMSDFMode sets the MSDF rendering mode
 func (rq *RenderQueue) SetMSDFMode(mode int) {
 	rq.primitiveBuffer.SetMSDFMode(mode)
 }
 
+// SetTextStyles sets the text style table used by styled MSDF text
+func (rq *RenderQueue) SetTextStyles(styles []graphics.TextStyleParams) {
+	rq.primitiveBuffer.SetTextStyles(styles)
+}
+
 // EnableSnapMSDFToPixels enables pixel snapping for MSDF
 func (rq *RenderQueue) EnableSnapMSDFToPixels(enable bool) {
 	rq.primitiveBuffer.EnableSnapMSDFToPixels(enable)
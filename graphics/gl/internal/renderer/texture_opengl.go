@@ -188,6 +188,9 @@ func (t *Texture) UpdateImage(img image.Image) error {
 
 		ctx.BindTexture(glapi.TEXTURE_2D, t.textureID)
 		ctx.TexImage2D(glapi.TEXTURE_2D, 0, glapi.RGBA, width, height, 0, glapi.RGBA, glapi.UNSIGNED_BYTE, rgbaImg.Pix)
+
+		// The quad size is derived from the image size
+		t.updateVertexBuffer()
 	} else {
 		ctx.BindTexture(glapi.TEXTURE_2D, t.textureID)
 		ctx.TexSubImage2D(glapi.TEXTURE_2D, 0, 0, 0, width, height, glapi.RGBA, glapi.UNSIGNED_BYTE, rgbaImg.Pix)
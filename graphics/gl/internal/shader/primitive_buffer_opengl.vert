@@ -1,7 +1,7 @@
 #version 410 core
 
 // Primitive buffer shader - vertex buffer based for OpenGL 4.1 compatibility
-// Uses PrimitiveVertex format: Position[3], LocalPosition[2], OpCode, Radius, Color[4], TexCoords[2], HalfSize[2]
+// Uses PrimitiveVertex format: Position[3], LocalPosition[2], OpCode, Radius, Color[4], TexCoords[2], HalfSize[2], AtlasSlot
 
 layout(location = 0) in vec3 a_position;
 layout(location = 1) in vec2 a_local_pos;
@@ -10,6 +10,7 @@ layout(location = 3) in float a_radius;
 layout(location = 4) in vec4 a_color;
 layout(location = 5) in vec2 a_tex_coords;
 layout(location = 6) in vec2 a_half_size;
+layout(location = 7) in float a_atlas_slot;
 
 uniform vec2 u_screen_size;
 
@@ -19,6 +20,7 @@ out float v_radius;
 out vec4 v_color;
 out vec2 v_tex_coords;
 out vec2 v_half_size;
+out float v_atlas_slot;
 
 void main() {
     gl_Position = vec4(a_position.xy, 0.0, 1.0);
@@ -28,4 +30,5 @@ void main() {
     v_color = a_color;
     v_tex_coords = a_tex_coords;
     v_half_size = a_half_size;
+    v_atlas_slot = a_atlas_slot;
 }
@@ -19,9 +19,18 @@ in float v_radius;
 in vec4 v_color;
 in vec2 v_tex_coords;
 in vec2 v_half_size;
+in float v_atlas_slot;
 
-uniform sampler2D u_msdf_atlas;
-uniform vec4 u_msdf_params; // x=px_range, y=tex_width, z=tex_height, w=msdf_mode
+// One atlas per slot so glyphs from fallback fonts can share a batch.
+// The array sizes, sampleAtlas branches and slot clamp in main must match
+// graphics.MaxMSDFAtlasSlots; change them together.
+uniform sampler2D u_msdf_atlases[4];
+uniform vec4 u_msdf_params[4]; // per slot: x=px_range, y=tex_width, z=tex_height, w=msdf_mode
+
+// Text styles selected by v_radius on MSDF primitives, style 0 is plain text.
+// Three vec4 per style: outline color, glow color, params (x=outline_width, y=glow_radius, z=softness).
+// The array size and style clamp in styledText must match graphics.MaxTextStyles.
+uniform vec4 u_text_styles[48];
 
 out vec4 frag_color;
 
@@ -61,9 +70,10 @@ float sdSegment(vec2 p, vec2 a, vec2 b) {
     return length(pa - ba * h);
 }
 
-float screenPxRange(vec2 tex_coords) {
-    float px_range = u_msdf_params.x;
-    vec2 tex_size = vec2(u_msdf_params.y, u_msdf_params.z);
+float screenPxRange(int slot, vec2 tex_coords) {
+    vec4 params = u_msdf_params[slot];
+    float px_range = params.x;
+    vec2 tex_size = vec2(params.y, params.z);
 
     vec2 unit_range = vec2(px_range) / tex_size;
     vec2 screen_tex_size = vec2(1.0) / fwidth(tex_coords);
@@ -71,14 +81,63 @@ float screenPxRange(vec2 tex_coords) {
     return max(0.5 * dot(unit_range, screen_tex_size), 1.5);
 }
 
+// Sampler arrays can only be indexed with constant expressions
+vec4 sampleAtlas(int slot, vec2 uv) {
+    if (slot == 1) {
+        return texture(u_msdf_atlases[1], uv);
+    } else if (slot == 2) {
+        return texture(u_msdf_atlases[2], uv);
+    } else if (slot == 3) {
+        return texture(u_msdf_atlases[3], uv);
+    }
+    return texture(u_msdf_atlases[0], uv);
+}
+
 // Sample MSDF and return signed distance
-float sampleMSDF(vec2 uv) {
-    vec4 mtsdf = texture(u_msdf_atlas, uv);
+float sampleMSDF(int slot, vec2 uv) {
+    vec4 mtsdf = sampleAtlas(slot, uv);
     float sd_rgb = median3(mtsdf.r, mtsdf.g, mtsdf.b);
     float sd_a = mtsdf.a;
     return max(sd_rgb, sd_a);
 }
 
+// Coverage of an edge at signed distance d in screen pixels, softened outwards by softness
+float edgeCoverage(float d, float softness) {
+    return smoothstep(-0.5 - softness, 0.5, d);
+}
+
+// Layers glow, outline and fill from the distance to the glyph edge. Outline and glow use the
+// true distance in the alpha channel, which stays accurate away from the edge where the
+// median of RGB does not. Each layer only covers what the layer above leaves, so text with a
+// transparent fill draws as a hollow outline.
+vec4 styledText(int style, int slot, vec2 uv, float px_range, float fill_dist) {
+    style = min(style, 15) * 3;
+    vec4 outline_color = u_text_styles[style];
+    vec4 glow_color = u_text_styles[style + 1];
+    vec4 params = u_text_styles[style + 2];
+    float softness = max(params.z, 0.0);
+
+    float true_dist = px_range * (sampleAtlas(slot, uv).a - 0.5);
+    float fill = edgeCoverage(softness > 0.0 ? true_dist : fill_dist, softness);
+    float outer = max(edgeCoverage(true_dist + params.x, softness), fill);
+    float glow = 0.0;
+    if (params.y > 0.0) {
+        glow = 1.0 - smoothstep(0.0, params.y, -(true_dist + params.x));
+    }
+
+    // Composite front to back with premultiplied alpha
+    float fill_a = v_color.a * fill;
+    float outline_a = outline_color.a * (outer - fill);
+    float glow_a = glow_color.a * glow * (1.0 - outer);
+    vec4 result = vec4(v_color.rgb * fill_a, fill_a);
+    result += vec4(outline_color.rgb * outline_a, outline_a) * (1.0 - result.a);
+    result += vec4(glow_color.rgb * glow_a, glow_a) * (1.0 - result.a);
+    if (result.a <= 0.0) {
+        return vec4(0.0);
+    }
+    return vec4(result.rgb / result.a, result.a);
+}
+
 void main() {
     frag_color = vec4(0.0, 0.0, 0.0, 0.0);
 
@@ -110,24 +169,35 @@ void main() {
         }
     } else if (op_code == int(OP_CODE_MSDF)) {
         // MTSDF text rendering with 4x supersampling for better quality on low-DPI displays
-        float msdf_pxRange = screenPxRange(v_tex_coords);
+        int slot = clamp(int(v_atlas_slot + 0.5), 0, 3);
+        float msdf_pxRange = screenPxRange(slot, v_tex_coords);
 
         // Calculate texel size for supersampling offsets
-        vec2 tex_size = vec2(u_msdf_params.y, u_msdf_params.z);
+        vec2 tex_size = vec2(u_msdf_params[slot].y, u_msdf_params[slot].z);
         vec2 texelSize = 1.0 / tex_size;
 
         // 4x rotated grid supersampling (reduces aliasing better than regular grid)
         vec2 offset = texelSize * 0.375;
-        float sd0 = sampleMSDF(v_tex_coords + vec2(-offset.x, -offset.y * 0.5));
-        float sd1 = sampleMSDF(v_tex_coords + vec2(offset.x, -offset.y * 0.5));
-        float sd2 = sampleMSDF(v_tex_coords + vec2(-offset.x * 0.5, offset.y));
-        float sd3 = sampleMSDF(v_tex_coords + vec2(offset.x * 0.5, offset.y));
+        float sd0 = sampleMSDF(slot, v_tex_coords + vec2(-offset.x, -offset.y * 0.5));
+        float sd1 = sampleMSDF(slot, v_tex_coords + vec2(offset.x, -offset.y * 0.5));
+        float sd2 = sampleMSDF(slot, v_tex_coords + vec2(-offset.x * 0.5, offset.y));
+        float sd3 = sampleMSDF(slot, v_tex_coords + vec2(offset.x * 0.5, offset.y));
 
         // Average the samples
         float sd = (sd0 + sd1 + sd2 + sd3) * 0.25;
 
         // Convert to screen pixels and apply anti-aliasing
         float screenPxDist = msdf_pxRange * (sd - 0.5);
+
+        int style = int(v_radius + 0.5);
+        if (style > 0) {
+            frag_color = styledText(style, slot, v_tex_coords, msdf_pxRange, screenPxDist);
+            if (frag_color.a < 0.005) {
+                discard;
+            }
+            return;
+        }
+
         float opacity = clamp(screenPxDist + 0.5, 0.0, 1.0);
 
         if (opacity < 0.005) {
@@ -0,0 +1,13 @@
+//go:build !js
+
+package window
+
+// GetClipboardText returns the text on the system clipboard, or "" if it holds no text.
+func (w *Window) GetClipboardText() string {
+	return w.Window.GetClipboardString()
+}
+
+// SetClipboardText puts text on the system clipboard.
+func (w *Window) SetClipboardText(text string) {
+	w.Window.SetClipboardString(text)
+}
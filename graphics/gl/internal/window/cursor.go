@@ -0,0 +1,70 @@
+//go:build !js
+
+package window
+
+import (
+	"image"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+var standardCursors = map[input.CursorShape]glfw.StandardCursor{
+	input.CursorArrow:     glfw.ArrowCursor,
+	input.CursorIBeam:     glfw.IBeamCursor,
+	input.CursorCrosshair: glfw.CrosshairCursor,
+	input.CursorHand:      glfw.HandCursor,
+	input.CursorResizeH:   glfw.HResizeCursor,
+	input.CursorResizeV:   glfw.VResizeCursor,
+}
+
+// SetCursorShape shows a standard cursor over the window. Shapes GLFW has no cursor
+// for show the arrow.
+func (w *Window) SetCursorShape(shape input.CursorShape) {
+	std, ok := standardCursors[shape]
+	if !ok {
+		std = glfw.ArrowCursor
+	}
+	if w.cursors == nil {
+		w.cursors = make(map[glfw.StandardCursor]*glfw.Cursor)
+	}
+	cursor, ok := w.cursors[std]
+	if !ok {
+		cursor = glfw.CreateStandardCursor(std)
+		w.cursors[std] = cursor
+	}
+	w.Window.SetCursor(cursor)
+}
+
+// SetCursorImage shows img as the cursor over the window, with its hot spot at
+// (hotX, hotY) in the image.
+func (w *Window) SetCursorImage(img image.Image, hotX, hotY int) {
+	cursor := glfw.CreateCursor(img, hotX, hotY)
+	w.Window.SetCursor(cursor)
+	if w.customCursor != nil {
+		w.customCursor.Destroy()
+	}
+	w.customCursor = cursor
+}
+
+// SetCursorMode shows, hides or locks the cursor. Locked cursors report raw, unscaled
+// motion where the platform supports it.
+func (w *Window) SetCursorMode(mode input.CursorMode) {
+	switch mode {
+	case input.CursorHidden:
+		w.Window.SetInputMode(glfw.CursorMode, glfw.CursorHidden)
+	case input.CursorLocked:
+		w.Window.SetInputMode(glfw.CursorMode, glfw.CursorDisabled)
+	default:
+		w.Window.SetInputMode(glfw.CursorMode, glfw.CursorNormal)
+	}
+	if glfw.RawMouseMotionSupported() {
+		raw := glfw.False
+		if mode == input.CursorLocked {
+			raw = glfw.True
+		}
+		w.Window.SetInputMode(glfw.RawMouseMotion, raw)
+	}
+	// The cursor jumps when it is locked or released; do not report the jump as motion
+	w.hasLastCursor = false
+}
@@ -0,0 +1,83 @@
+//go:build !js
+
+package window
+
+import (
+	"github.com/dfirebaugh/hlg/graphics"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// Monitors returns the monitors connected, the primary monitor first.
+func (w *Window) Monitors() []graphics.Monitor {
+	primary := glfw.GetPrimaryMonitor()
+	var monitors []graphics.Monitor
+	for _, m := range glfw.GetMonitors() {
+		x, y := m.GetPos()
+		sx, sy := m.GetContentScale()
+		monitor := graphics.Monitor{
+			Name:    m.GetName(),
+			X:       x,
+			Y:       y,
+			ScaleX:  sx,
+			ScaleY:  sy,
+			Primary: m == primary,
+		}
+		if mode := m.GetVideoMode(); mode != nil {
+			monitor.Width, monitor.Height, monitor.RefreshRate = mode.Width, mode.Height, mode.RefreshRate
+		}
+		for _, mode := range m.GetVideoModes() {
+			monitor.Modes = append(monitor.Modes, graphics.VideoMode{
+				Width: mode.Width, Height: mode.Height, RefreshRate: mode.RefreshRate,
+			})
+		}
+		monitors = append(monitors, monitor)
+	}
+	return monitors
+}
+
+// SetFullscreen makes the window fullscreen on a monitor, an index into Monitors, in
+// the given video mode. A zero mode, or a zero refresh rate, keeps the monitor's
+// current one. An index out of range uses the primary monitor.
+func (w *Window) SetFullscreen(monitor int, mode graphics.VideoMode) {
+	m := glfw.GetPrimaryMonitor()
+	if monitors := glfw.GetMonitors(); monitor >= 0 && monitor < len(monitors) {
+		m = monitors[monitor]
+	}
+	current := m.GetVideoMode()
+	if mode.Width <= 0 || mode.Height <= 0 {
+		mode.Width, mode.Height = current.Width, current.Height
+	}
+	if mode.RefreshRate <= 0 {
+		mode.RefreshRate = current.RefreshRate
+	}
+	w.saveWindowed()
+	w.Window.SetMonitor(m, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+}
+
+// SetWindowed leaves fullscreen, restoring the position and size the window had before.
+func (w *Window) SetWindowed() {
+	if w.Window.GetMonitor() == nil {
+		return
+	}
+	if !w.hasWindowed {
+		w.windowedX, w.windowedY = 100, 100
+		w.windowedW, w.windowedH = w.targetWidth, w.targetHeight
+	}
+	w.Window.SetMonitor(nil, w.windowedX, w.windowedY, w.windowedW, w.windowedH, 0)
+}
+
+// IsFullscreen returns true if the window is fullscreen on a monitor.
+func (w *Window) IsFullscreen() bool {
+	return w.Window.GetMonitor() != nil
+}
+
+// saveWindowed remembers the position and size of the window before it goes
+// fullscreen, for SetWindowed.
+func (w *Window) saveWindowed() {
+	if w.Window.GetMonitor() != nil {
+		return
+	}
+	w.windowedX, w.windowedY = w.Window.GetPos()
+	w.windowedW, w.windowedH = w.Window.GetSize()
+	w.hasWindowed = true
+}
@@ -21,6 +21,8 @@ type GraphicsBackend interface {
 	TextureManager
 	ShapeRenderer
 	InputManager
+	CursorManager
+	ClipboardManager
 	ShaderManager
 	FontManager
 }
@@ -98,6 +100,18 @@ type Transformable interface {
 type WindowManager interface {
 	DisableWindowResize()
 	SetBorderlessWindowed(v bool)
+	Monitors() []Monitor
+	SetFullscreen(monitor int, mode VideoMode)
+	SetWindowed()
+	IsFullscreen() bool
+	SetWindowIcon(images []image.Image)
+	SetWindowPosition(x, y int)
+	SetWindowSizeLimits(minWidth, minHeight, maxWidth, maxHeight int)
+	SetAlwaysOnTop(v bool)
+	MinimizeWindow()
+	MaximizeWindow()
+	RestoreWindow()
+	RequestAttention()
 	SetWindowTitle(title string)
 	DestroyWindow()
 	SetWindowSize(width int, height int)
@@ -108,6 +122,29 @@ type WindowManager interface {
 	Renderer
 }
 
+// ClipboardManager reads and writes the system clipboard.
+type ClipboardManager interface {
+	GetClipboardText() string
+	SetClipboardText(text string)
+}
+
+// Monitor describes a display connected to the system.
+type Monitor struct {
+	Name           string
+	X, Y           int     // position on the desktop, in screen coordinates
+	Width, Height  int     // size of the current video mode
+	RefreshRate    int     // refresh rate of the current video mode in Hz, or 0 if unknown
+	ScaleX, ScaleY float32 // content scale: pixels per screen coordinate the system suggests
+	Primary        bool
+	Modes          []VideoMode // supported video modes, smallest first
+}
+
+// VideoMode is a resolution and refresh rate a monitor can be driven at.
+type VideoMode struct {
+	Width, Height int
+	RefreshRate   int // in Hz
+}
+
 type TextureManager interface {
 	CreateTextureFromImage(img image.Image) (Texture, error)
 	DisposeTexture(h uintptr)
@@ -138,6 +175,35 @@ const (
 	OpCodeLine        float32 = 5.0 // Line segment SDF
 )
 
+// MaxMSDFAtlasSlots is the number of MSDF atlases the primitive buffer can sample
+// within a single batch. Slot 0 holds the active atlas set by SetMSDFAtlas; the
+// remaining slots are used for font fallback chains.
+//
+// The primitive buffer shaders hard-code this count (primitive_buffer_*.frag in the
+// gl backend and primitive_buffer.wgsl in the webgpu backend, including the extra
+// texture bindings there). Change them together with this constant.
+const MaxMSDFAtlasSlots = 4
+
+// MaxTextStyles is the number of text styles the primitive buffer can hold at once.
+// MSDF primitives select a style through their Radius field; style 0 is plain text.
+//
+// The primitive buffer shaders hard-code this count (u_text_styles in the gl backend
+// and text_styles in the webgpu backend). Change them together with this constant.
+const MaxTextStyles = 16
+
+// TextStyleParams are the effects applied to MSDF primitives that use a text style.
+// Distances are in screen pixels measured from the glyph edge, and are limited by the
+// distance range of the atlas at the size the text is drawn.
+// IMPORTANT: This struct must match the WGSL TextStyle layout (three vec4<f32>).
+type TextStyleParams struct {
+	OutlineColor [4]float32 // bytes 0-15: RGBA outline color
+	GlowColor    [4]float32 // bytes 16-31: RGBA glow color
+	OutlineWidth float32    // bytes 32-35: outline width outside the glyph edge
+	GlowRadius   float32    // bytes 36-39: distance beyond the outline the glow fades over
+	Softness     float32    // bytes 40-43: extra edge softness, used to blur shadows
+	_            float32    // bytes 44-47: padding
+}
+
 // PrimitiveVertex is the vertex format used by the primitive buffer for SDF rendering
 // DEPRECATED: Use Primitive instead for the new storage buffer approach
 // Note: This struct is uploaded to GPU, so it cannot contain Go pointers.
@@ -146,10 +212,11 @@ type PrimitiveVertex struct {
 	Position      [3]float32 // Clip space position
 	LocalPosition [2]float32 // Local coordinates for SDF calculation
 	OpCode        float32    // Rendering operation code
-	Radius        float32    // Corner radius or circle radius
+	Radius        float32    // Corner radius or circle radius; text style index for MSDF text
 	Color         [4]float32 // RGBA color
 	TexCoords     [2]float32 // UV coordinates for MSDF text, or line direction
 	HalfSize      [2]float32 // Half width/height of bounding box (for OpenGL SDF)
+	AtlasSlot     float32    // MSDF atlas slot to sample from (0 = active atlas)
 }
 
 // Primitive is a compact representation for the storage buffer approach.
@@ -160,9 +227,10 @@ type PrimitiveVertex struct {
 type Primitive struct {
 	X, Y, W, H float32    // bytes 0-15: bounding box in screen space
 	Color      [4]float32 // bytes 16-31: RGBA color (vec4, 16-byte aligned)
-	Radius     float32    // bytes 32-35: corner radius or circle radius
+	Radius     float32    // bytes 32-35: corner radius or circle radius; for MSDF: text style index (0 = plain)
 	OpCode     float32    // bytes 36-39: primitive type
-	_          [2]float32 // bytes 40-47: padding to align Extra to 16 bytes
+	AtlasSlot  float32    // bytes 40-43: MSDF atlas slot to sample from (0 = active atlas)
+	_          float32    // bytes 44-47: padding to align Extra to 16 bytes
 	Extra      [4]float32 // bytes 48-63: for MSDF: (u0, v0, u_size, v_size); for shapes: (half_w, half_h, 0, 0)
 	ClipRect   *[4]int    // optional clip rect (x, y, width, height) - nil means no clipping
 }
@@ -181,7 +249,9 @@ type ShapeRenderer interface {
 	DrawPrimitives(primitives []Primitive)                                            // New storage buffer approach
 	FlushPrimitiveBuffer()                                                            // Force immediate render of pending primitives
 	SetMSDFAtlas(atlasImg image.Image, pxRange float64)
+	SetMSDFAtlasSlot(slot int, atlasImg image.Image, pxRange float64) // Load an atlas into one of MaxMSDFAtlasSlots
 	SetMSDFMode(mode int)
+	SetTextStyles(styles []TextStyleParams) // Load the text style table; index 0 is plain text and ignored
 	EnableSnapMSDFToPixels(enable bool)
 }
 
@@ -200,6 +270,13 @@ type InputManager interface {
 	SetInputCallback(fn func(eventChan chan input.Event))
 }
 
+// CursorManager controls the mouse cursor over the window.
+type CursorManager interface {
+	SetCursorShape(shape input.CursorShape)
+	SetCursorImage(img image.Image, hotX, hotY int)
+	SetCursorMode(mode input.CursorMode)
+}
+
 type Uniform struct {
 	Binding uint32
 	Size    uint64
@@ -248,6 +325,10 @@ type FontMetrics struct {
 type MSDFAtlas interface {
 	AddGlyph(r rune, info *GlyphInfo)
 	GetGlyph(r rune) *GlyphInfo
+	// AddGlyphByIndex and GetGlyphByIndex key glyphs by their glyph index in the font,
+	// which is what a text shaper produces.
+	AddGlyphByIndex(index int, info *GlyphInfo)
+	GetGlyphByIndex(index int) *GlyphInfo
 	SetMetrics(metrics FontMetrics)
 	GetMetrics() FontMetrics
 	Dispose()
@@ -265,3 +346,11 @@ type FontManager interface {
 type GlyphDrawer interface {
 	DrawGlyph(x0, y0, x1, y1 float32, u0, v0, u1, v1 float32, c color.Color)
 }
+
+// AtlasGlyphDrawer is a GlyphDrawer that can also draw glyphs from a specific MSDF
+// atlas slot. Fonts with fallback chains need this to draw glyphs that come from a
+// fallback font's atlas.
+type AtlasGlyphDrawer interface {
+	GlyphDrawer
+	DrawAtlasGlyph(x0, y0, x1, y1 float32, u0, v0, u1, v1 float32, atlasSlot int, c color.Color)
+}
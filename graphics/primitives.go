@@ -468,6 +468,7 @@ func ConvertPrimitivesToVertices(primitives []Primitive, screenW, screenH float3
 				Color:         prim.Color,
 				TexCoords:     texCoords[texCoordIndices[i]],
 				HalfSize:      halfSize,
+				AtlasSlot:     prim.AtlasSlot,
 			})
 		}
 	}
@@ -25,7 +25,8 @@ type MSDFAtlas struct {
 	metrics graphics.FontMetrics
 
 	// Glyph lookup
-	glyphs map[rune]*graphics.GlyphInfo
+	glyphs        map[rune]*graphics.GlyphInfo
+	glyphsByIndex map[int]*graphics.GlyphInfo
 
 	isDisposed bool
 }
@@ -43,6 +44,7 @@ func NewMSDFAtlas(ctx context.RenderContext, atlasImg image.Image, distanceRange
 		height:        height,
 		distanceRange: distanceRange,
 		glyphs:        make(map[rune]*graphics.GlyphInfo),
+		glyphsByIndex: make(map[int]*graphics.GlyphInfo),
 	}
 
 	return atlas, nil
@@ -58,6 +60,16 @@ func (a *MSDFAtlas) GetGlyph(r rune) *graphics.GlyphInfo {
 	return a.glyphs[r]
 }
 
+// AddGlyphByIndex adds glyph information keyed by the glyph's index in the font
+func (a *MSDFAtlas) AddGlyphByIndex(index int, info *graphics.GlyphInfo) {
+	a.glyphsByIndex[index] = info
+}
+
+// GetGlyphByIndex returns the glyph info for a glyph index, or nil if not found
+func (a *MSDFAtlas) GetGlyphByIndex(index int) *graphics.GlyphInfo {
+	return a.glyphsByIndex[index]
+}
+
 // SetMetrics sets the font metrics
 func (a *MSDFAtlas) SetMetrics(metrics graphics.FontMetrics) {
 	a.metrics = metrics
@@ -38,12 +38,16 @@ type PrimitiveBuffer struct {
 	screenSizeBuffer *wgpu.Buffer
 	screenSize       [2]float32
 
-	// MSDF atlas resources
-	msdfTexture      *wgpu.Texture
-	msdfTextureView  *wgpu.TextureView
+	// MSDF atlas resources, one texture per atlas slot
+	msdfTextures     [graphics.MaxMSDFAtlasSlots]*wgpu.Texture
+	msdfTextureViews [graphics.MaxMSDFAtlasSlots]*wgpu.TextureView
 	msdfSampler      *wgpu.Sampler
 	msdfParamsBuffer *wgpu.Buffer
-	msdfParams       [4]float32 // px_range, tex_width, tex_height, unused
+	msdfParams       [graphics.MaxMSDFAtlasSlots][4]float32 // per slot: px_range, tex_width, tex_height, msdf_mode
+
+	// Uniform buffer for the text style table
+	textStylesBuffer *wgpu.Buffer
+	textStyles       [graphics.MaxTextStyles]graphics.TextStyleParams
 
 	isDisposed bool
 }
@@ -68,9 +72,11 @@ func NewPrimitiveBuffer(ctx context.RenderContext, primitives []graphics.Primiti
 		RenderContext: ctx,
 		primitives:    primitives,
 		primitivesCap: max(len(primitives), 1024), // initial capacity
-		msdfParams:    [4]float32{4.0, 1.0, 1.0, 0.0},
 		screenSize:    [2]float32{float32(sw), float32(sh)},
 	}
+	for slot := range p.msdfParams {
+		p.msdfParams[slot] = [4]float32{4.0, 1.0, 1.0, 0.0}
+	}
 
 	p.createScreenSizeBuffer()
 	p.createMSDFResources()
@@ -98,11 +104,10 @@ func NewPrimitiveBuffer(ctx context.RenderContext, primitives []graphics.Primiti
 	}
 
 	// Create solid shape pipeline (vertex buffer based, for PrimitiveShape)
-	// PrimitiveVertex layout: Position[3], LocalPosition[2], OpCode, Radius, Color[4], TexCoords[2]
-	// Total: 3+2+1+1+4+2 = 13 floats = 52 bytes
+	// PrimitiveVertex layout: Position[3], LocalPosition[2], OpCode, Radius, Color[4], TexCoords[2], HalfSize[2], AtlasSlot
 	solidShapeLayout := []wgpu.VertexBufferLayout{
 		{
-			ArrayStride: 52, // sizeof(PrimitiveVertex)
+			ArrayStride: uint64(unsafe.Sizeof(graphics.PrimitiveVertex{})),
 			StepMode:    wgpu.VertexStepMode_Vertex,
 			Attributes: []wgpu.VertexAttribute{
 				{Format: wgpu.VertexFormat_Float32x3, Offset: 0, ShaderLocation: 0},  // position
@@ -183,43 +188,45 @@ func (p *PrimitiveBuffer) createScreenSizeBuffer() {
 func (p *PrimitiveBuffer) createMSDFResources() {
 	var err error
 
-	// Create a 1x1 placeholder texture
-	p.msdfTexture, err = p.GetDevice().CreateTexture(&wgpu.TextureDescriptor{
-		Label: "MSDF Placeholder Texture",
-		Size: wgpu.Extent3D{
-			Width:              1,
-			Height:             1,
-			DepthOrArrayLayers: 1,
-		},
-		MipLevelCount: 1,
-		SampleCount:   1,
-		Dimension:     wgpu.TextureDimension_2D,
-		Format:        wgpu.TextureFormat_RGBA8Unorm,
-		Usage:         wgpu.TextureUsage_TextureBinding | wgpu.TextureUsage_CopyDst,
-	})
-	if err != nil {
-		log.Fatalf("Failed to create placeholder MSDF texture: %v", err)
-	}
+	// Create a 1x1 placeholder texture for every atlas slot
+	for slot := range p.msdfTextures {
+		p.msdfTextures[slot], err = p.GetDevice().CreateTexture(&wgpu.TextureDescriptor{
+			Label: "MSDF Placeholder Texture",
+			Size: wgpu.Extent3D{
+				Width:              1,
+				Height:             1,
+				DepthOrArrayLayers: 1,
+			},
+			MipLevelCount: 1,
+			SampleCount:   1,
+			Dimension:     wgpu.TextureDimension_2D,
+			Format:        wgpu.TextureFormat_RGBA8Unorm,
+			Usage:         wgpu.TextureUsage_TextureBinding | wgpu.TextureUsage_CopyDst,
+		})
+		if err != nil {
+			log.Fatalf("Failed to create placeholder MSDF texture: %v", err)
+		}
 
-	_ = p.GetDevice().GetQueue().WriteTexture(
-		&wgpu.ImageCopyTexture{
-			Aspect:   wgpu.TextureAspect_All,
-			Texture:  p.msdfTexture,
-			MipLevel: 0,
-			Origin:   wgpu.Origin3D{X: 0, Y: 0, Z: 0},
-		},
-		[]byte{0, 0, 0, 0},
-		&wgpu.TextureDataLayout{
-			Offset:       0,
-			BytesPerRow:  4,
-			RowsPerImage: 1,
-		},
-		&wgpu.Extent3D{Width: 1, Height: 1, DepthOrArrayLayers: 1},
-	)
+		_ = p.GetDevice().GetQueue().WriteTexture(
+			&wgpu.ImageCopyTexture{
+				Aspect:   wgpu.TextureAspect_All,
+				Texture:  p.msdfTextures[slot],
+				MipLevel: 0,
+				Origin:   wgpu.Origin3D{X: 0, Y: 0, Z: 0},
+			},
+			[]byte{0, 0, 0, 0},
+			&wgpu.TextureDataLayout{
+				Offset:       0,
+				BytesPerRow:  4,
+				RowsPerImage: 1,
+			},
+			&wgpu.Extent3D{Width: 1, Height: 1, DepthOrArrayLayers: 1},
+		)
 
-	p.msdfTextureView, err = p.msdfTexture.CreateView(nil)
-	if err != nil {
-		log.Fatalf("Failed to create MSDF texture view: %v", err)
+		p.msdfTextureViews[slot], err = p.msdfTextures[slot].CreateView(nil)
+		if err != nil {
+			log.Fatalf("Failed to create MSDF texture view: %v", err)
+		}
 	}
 
 	p.msdfSampler, err = p.GetDevice().CreateSampler(&wgpu.SamplerDescriptor{
@@ -243,11 +250,20 @@ func (p *PrimitiveBuffer) createMSDFResources() {
 	if err != nil {
 		log.Fatalf("Failed to create MSDF params buffer: %v", err)
 	}
+
+	p.textStylesBuffer, err = p.GetDevice().CreateBufferInit(&wgpu.BufferInitDescriptor{
+		Label:    "Text Styles Buffer",
+		Usage:    wgpu.BufferUsage_Uniform | wgpu.BufferUsage_CopyDst,
+		Contents: wgpu.ToBytes(p.textStyles[:]),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create text styles buffer: %v", err)
+	}
 }
 
 func (p *PrimitiveBuffer) createBindGroupLayout() {
 	var err error
-	p.bindGroupLayout, err = p.GetDevice().CreateBindGroupLayout(&wgpu.BindGroupLayoutDescriptor{
+	desc := &wgpu.BindGroupLayoutDescriptor{
 		Label: "Primitive Buffer Bind Group Layout",
 		Entries: []wgpu.BindGroupLayoutEntry{
 			{
@@ -293,7 +309,31 @@ func (p *PrimitiveBuffer) createBindGroupLayout() {
 				},
 			},
 		},
+	}
+
+	// Atlas slots after the first are appended as bindings 5, 6, ...
+	for slot := 1; slot < graphics.MaxMSDFAtlasSlots; slot++ {
+		desc.Entries = append(desc.Entries, wgpu.BindGroupLayoutEntry{
+			Binding:    uint32(4 + slot),
+			Visibility: wgpu.ShaderStage_Fragment,
+			Texture: wgpu.TextureBindingLayout{
+				Multisampled:  false,
+				ViewDimension: wgpu.TextureViewDimension_2D,
+				SampleType:    wgpu.TextureSampleType_Float,
+			},
+		})
+	}
+
+	// Text styles uniform follows the atlas slots
+	desc.Entries = append(desc.Entries, wgpu.BindGroupLayoutEntry{
+		Binding:    uint32(4 + graphics.MaxMSDFAtlasSlots),
+		Visibility: wgpu.ShaderStage_Fragment,
+		Buffer: wgpu.BufferBindingLayout{
+			Type: wgpu.BufferBindingType_Uniform,
+		},
 	})
+
+	p.bindGroupLayout, err = p.GetDevice().CreateBindGroupLayout(desc)
 	if err != nil {
 		log.Fatalf("Failed to create bind group layout: %v", err)
 	}
@@ -308,7 +348,7 @@ func (p *PrimitiveBuffer) createBindGroup() {
 		storageBufferSize = primitiveSize
 	}
 
-	p.bindGroup, err = p.GetDevice().CreateBindGroup(&wgpu.BindGroupDescriptor{
+	desc := &wgpu.BindGroupDescriptor{
 		Label:  "Primitive Buffer Bind Group",
 		Layout: p.bindGroupLayout,
 		Entries: []wgpu.BindGroupEntry{
@@ -326,7 +366,7 @@ func (p *PrimitiveBuffer) createBindGroup() {
 			},
 			{
 				Binding:     2,
-				TextureView: p.msdfTextureView,
+				TextureView: p.msdfTextureViews[0],
 			},
 			{
 				Binding: 3,
@@ -339,7 +379,21 @@ func (p *PrimitiveBuffer) createBindGroup() {
 				Size:    uint64(unsafe.Sizeof(p.msdfParams)),
 			},
 		},
+	}
+	for slot := 1; slot < graphics.MaxMSDFAtlasSlots; slot++ {
+		desc.Entries = append(desc.Entries, wgpu.BindGroupEntry{
+			Binding:     uint32(4 + slot),
+			TextureView: p.msdfTextureViews[slot],
+		})
+	}
+	desc.Entries = append(desc.Entries, wgpu.BindGroupEntry{
+		Binding: uint32(4 + graphics.MaxMSDFAtlasSlots),
+		Buffer:  p.textStylesBuffer,
+		Offset:  0,
+		Size:    uint64(unsafe.Sizeof(p.textStyles)),
 	})
+
+	p.bindGroup, err = p.GetDevice().CreateBindGroup(desc)
 	if err != nil {
 		log.Fatalf("Failed to create bind group: %v", err)
 	}
@@ -347,6 +401,15 @@ func (p *PrimitiveBuffer) createBindGroup() {
 
 // SetMSDFAtlas sets the MSDF atlas texture for text rendering
 func (p *PrimitiveBuffer) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
+	p.SetMSDFAtlasSlot(0, atlasImg, pxRange)
+}
+
+// SetMSDFAtlasSlot sets the MSDF atlas texture for the given atlas slot
+func (p *PrimitiveBuffer) SetMSDFAtlasSlot(slot int, atlasImg image.Image, pxRange float64) {
+	if slot < 0 || slot >= graphics.MaxMSDFAtlasSlots {
+		return
+	}
+
 	r := atlasImg.Bounds()
 	width := r.Dx()
 	height := r.Dy()
@@ -357,11 +420,11 @@ func (p *PrimitiveBuffer) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
 		draw.Draw(rgbaImg, r, atlasImg, image.Point{}, draw.Over)
 	}
 
-	if p.msdfTextureView != nil {
-		p.msdfTextureView.Release()
+	if p.msdfTextureViews[slot] != nil {
+		p.msdfTextureViews[slot].Release()
 	}
-	if p.msdfTexture != nil {
-		p.msdfTexture.Release()
+	if p.msdfTextures[slot] != nil {
+		p.msdfTextures[slot].Release()
 	}
 
 	size := wgpu.Extent3D{
@@ -371,7 +434,7 @@ func (p *PrimitiveBuffer) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
 	}
 
 	var err error
-	p.msdfTexture, err = p.GetDevice().CreateTexture(&wgpu.TextureDescriptor{
+	p.msdfTextures[slot], err = p.GetDevice().CreateTexture(&wgpu.TextureDescriptor{
 		Label:         "MSDF Atlas Texture",
 		Size:          size,
 		MipLevelCount: 1,
@@ -388,7 +451,7 @@ func (p *PrimitiveBuffer) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
 	if err = p.GetDevice().GetQueue().WriteTexture(
 		&wgpu.ImageCopyTexture{
 			Aspect:   wgpu.TextureAspect_All,
-			Texture:  p.msdfTexture,
+			Texture:  p.msdfTextures[slot],
 			MipLevel: 0,
 			Origin:   wgpu.Origin3D{X: 0, Y: 0, Z: 0},
 		},
@@ -404,13 +467,13 @@ func (p *PrimitiveBuffer) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
 		return
 	}
 
-	p.msdfTextureView, err = p.msdfTexture.CreateView(nil)
+	p.msdfTextureViews[slot], err = p.msdfTextures[slot].CreateView(nil)
 	if err != nil {
 		log.Printf("Failed to create MSDF texture view: %v", err)
 		return
 	}
 
-	p.msdfParams = [4]float32{float32(pxRange), float32(width), float32(height), p.msdfParams[3]}
+	p.msdfParams[slot] = [4]float32{float32(pxRange), float32(width), float32(height), p.msdfParams[slot][3]}
 	_ = p.GetDevice().GetQueue().WriteBuffer(p.msdfParamsBuffer, 0, wgpu.ToBytes(p.msdfParams[:]))
 
 	// Recreate bind group with new texture
@@ -425,12 +488,28 @@ func (p *PrimitiveBuffer) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
 // Mode 1: alpha channel only (true SDF fallback)
 // Mode 2: visualize RGB channels directly (for debugging atlas)
 func (p *PrimitiveBuffer) SetMSDFMode(mode int) {
-	p.msdfParams[3] = float32(mode)
+	for slot := range p.msdfParams {
+		p.msdfParams[slot][3] = float32(mode)
+	}
 	if p.msdfParamsBuffer != nil {
 		_ = p.GetDevice().GetQueue().WriteBuffer(p.msdfParamsBuffer, 0, wgpu.ToBytes(p.msdfParams[:]))
 	}
 }
 
+// SetTextStyles loads the text style table. Style 0 is plain text and is left zero.
+func (p *PrimitiveBuffer) SetTextStyles(styles []graphics.TextStyleParams) {
+	for i := 1; i < graphics.MaxTextStyles; i++ {
+		if i < len(styles) {
+			p.textStyles[i] = styles[i]
+		} else {
+			p.textStyles[i] = graphics.TextStyleParams{}
+		}
+	}
+	if p.textStylesBuffer != nil {
+		_ = p.GetDevice().GetQueue().WriteBuffer(p.textStylesBuffer, 0, wgpu.ToBytes(p.textStyles[:]))
+	}
+}
+
 // UpdatePrimitives updates the storage buffer with new primitives
 func (p *PrimitiveBuffer) UpdatePrimitives(primitives []graphics.Primitive) {
 	if len(primitives) == 0 {
@@ -521,17 +600,23 @@ func (p *PrimitiveBuffer) Dispose() {
 		p.msdfParamsBuffer.Release()
 		p.msdfParamsBuffer = nil
 	}
+	if p.textStylesBuffer != nil {
+		p.textStylesBuffer.Release()
+		p.textStylesBuffer = nil
+	}
 	if p.msdfSampler != nil {
 		p.msdfSampler.Release()
 		p.msdfSampler = nil
 	}
-	if p.msdfTextureView != nil {
-		p.msdfTextureView.Release()
-		p.msdfTextureView = nil
-	}
-	if p.msdfTexture != nil {
-		p.msdfTexture.Release()
-		p.msdfTexture = nil
+	for slot := range p.msdfTextures {
+		if p.msdfTextureViews[slot] != nil {
+			p.msdfTextureViews[slot].Release()
+			p.msdfTextureViews[slot] = nil
+		}
+		if p.msdfTextures[slot] != nil {
+			p.msdfTextures[slot].Release()
+			p.msdfTextures[slot] = nil
+		}
 	}
 	if p.bindGroupLayout != nil {
 		p.bindGroupLayout.Release()
@@ -650,14 +735,15 @@ func convertVerticesToPrimitives(vertices []graphics.PrimitiveVertex, screenW, s
 		}
 
 		primitives = append(primitives, graphics.Primitive{
-			X:      minX,
-			Y:      minY,
-			W:      w,
-			H:      h,
-			Color:  v[0].Color,
-			Radius: v[0].Radius,
-			OpCode: opCode,
-			Extra:  extra,
+			X:         minX,
+			Y:         minY,
+			W:         w,
+			H:         h,
+			Color:     v[0].Color,
+			Radius:    v[0].Radius,
+			OpCode:    opCode,
+			AtlasSlot: v[0].AtlasSlot,
+			Extra:     extra,
 		})
 	}
 
@@ -335,10 +335,20 @@ func (rq *RenderQueue) SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
 	rq.PrimitiveBuffer.SetMSDFAtlas(atlasImg, pxRange)
 }
 
+// SetMSDFAtlasSlot sets the MSDF atlas texture for an atlas slot
+func (rq *RenderQueue) SetMSDFAtlasSlot(slot int, atlasImg image.Image, pxRange float64) {
+	rq.PrimitiveBuffer.SetMSDFAtlasSlot(slot, atlasImg, pxRange)
+}
+
 func (rq *RenderQueue) SetMSDFMode(mode int) {
 	rq.PrimitiveBuffer.SetMSDFMode(mode)
 }
 
+// SetTextStyles sets the text style table used by styled MSDF text
+func (rq *RenderQueue) SetTextStyles(styles []graphics.TextStyleParams) {
+	rq.PrimitiveBuffer.SetTextStyles(styles)
+}
+
 func (rq *RenderQueue) EnableSnapMSDFToPixels(enable bool) {
 	rq.PrimitiveBuffer.EnableSnapMSDFToPixels(enable)
 }
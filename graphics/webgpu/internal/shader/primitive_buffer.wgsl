@@ -22,7 +22,8 @@ struct Primitive {
     color: vec4<f32>, // offset 16 (16-byte aligned)
     radius: f32,      // offset 32
     op_code: f32,     // offset 36
-    _pad1: vec2<f32>, // offset 40 (padding to align extra)
+    atlas_slot: f32,  // offset 40 - MSDF atlas slot (0 = active atlas)
+    _pad1: f32,       // offset 44 (padding to align extra)
     extra: vec4<f32>, // offset 48 (16-byte aligned) - for MSDF (u0, v0, u_size, v_size); for shapes (half_w, half_h, 0, 0)
 }
 
@@ -32,11 +33,26 @@ struct Primitive {
 // Uniforms for screen size (needed for NDC conversion)
 @group(0) @binding(1) var<uniform> screen_size: vec2<f32>;
 
-// MSDF atlas resources
+// MSDF atlas resources (slot 0 at binding 2, further slots at bindings 5..7).
+// The slot count here (params array size, extra bindings, sampleAtlas cases and the
+// atlas_slot clamp in vs_main) must match graphics.MaxMSDFAtlasSlots; change them together.
 @group(0) @binding(2) var t_msdf_atlas: texture_2d<f32>;
 @group(0) @binding(3) var s_msdf_atlas: sampler;
-// x=px_range, y=tex_width, z=tex_height, w=msdf_mode (0=median RGB/MSDF, 1=alpha/true SDF, 2=visualize RGB)
-@group(0) @binding(4) var<uniform> u_msdf_params: vec4<f32>;
+// per slot: x=px_range, y=tex_width, z=tex_height, w=msdf_mode (0=median RGB/MSDF, 1=alpha/true SDF, 2=visualize RGB)
+@group(0) @binding(4) var<uniform> u_msdf_params: array<vec4<f32>, 4>;
+@group(0) @binding(5) var t_msdf_atlas_1: texture_2d<f32>;
+@group(0) @binding(6) var t_msdf_atlas_2: texture_2d<f32>;
+@group(0) @binding(7) var t_msdf_atlas_3: texture_2d<f32>;
+
+// Text styles selected by the radius of MSDF primitives, style 0 is plain text.
+// MUST match Go TextStyleParams layout. The binding follows the atlas slots, and the
+// array size and style clamp in styledText must match graphics.MaxTextStyles.
+struct TextStyle {
+    outline_color: vec4<f32>,
+    glow_color: vec4<f32>,
+    params: vec4<f32>, // x=outline_width, y=glow_radius, z=softness
+}
+@group(0) @binding(8) var<uniform> text_styles: array<TextStyle, 16>;
 
 struct VertexOutput {
     @builtin(position) clip_position: vec4<f32>,
@@ -46,6 +62,7 @@ struct VertexOutput {
     @location(3) color: vec4<f32>,
     @location(4) tex_coords: vec2<f32>,
     @location(5) half_size: vec2<f32>,
+    @location(6) @interpolate(flat) atlas_slot: u32,
 }
 
 // Colors come in as normalized 0..1 (sRGB-ish). We output directly to the swapchain format.
@@ -128,6 +145,7 @@ fn vs_main(@builtin(vertex_index) vertex_index: u32) -> VertexOutput {
     output.radius = prim.radius;
     output.color = vec4<f32>(srgbToLinear(prim.color.rgb), prim.color.a);
     output.half_size = vec2<f32>(prim.w, prim.h) * 0.5;
+    output.atlas_slot = u32(clamp(prim.atlas_slot + 0.5, 0.0, 3.0));
 
     // For MSDF: extra stores UV base (xy) and UV size (zw)
     if prim.op_code == OP_CODE_MSDF {
@@ -178,9 +196,9 @@ fn median(r: f32, g: f32, b: f32) -> f32 {
     return max(min(r, g), min(max(r, g), b));
 }
 
-fn screenPxRange(tex_coords: vec2<f32>) -> f32 {
-    let px_range = u_msdf_params.x;
-    let tex_size = vec2<f32>(u_msdf_params.y, u_msdf_params.z);
+fn screenPxRange(params: vec4<f32>, tex_coords: vec2<f32>) -> f32 {
+    let px_range = params.x;
+    let tex_size = vec2<f32>(params.y, params.z);
 
     // Standard MSDF screenPxRange calculation (msdfgen reference)
     let unit_range = vec2<f32>(px_range) / tex_size;
@@ -189,14 +207,59 @@ fn screenPxRange(tex_coords: vec2<f32>) -> f32 {
     return max(0.5 * dot(unit_range, screen_tex_size), 1.5);
 }
 
+// Sample the atlas for a slot. The atlases have a single mip level, so an explicit
+// level keeps sampling valid when the slot varies per primitive.
+fn sampleAtlas(slot: u32, uv: vec2<f32>) -> vec4<f32> {
+    switch slot {
+        case 1u: { return textureSampleLevel(t_msdf_atlas_1, s_msdf_atlas, uv, 0.0); }
+        case 2u: { return textureSampleLevel(t_msdf_atlas_2, s_msdf_atlas, uv, 0.0); }
+        case 3u: { return textureSampleLevel(t_msdf_atlas_3, s_msdf_atlas, uv, 0.0); }
+        default: { return textureSampleLevel(t_msdf_atlas, s_msdf_atlas, uv, 0.0); }
+    }
+}
+
 // Sample MSDF and return signed distance
-fn sampleMSDF(uv: vec2<f32>) -> f32 {
-    let mtsdf = textureSample(t_msdf_atlas, s_msdf_atlas, uv);
+fn sampleMSDF(slot: u32, uv: vec2<f32>) -> f32 {
+    let mtsdf = sampleAtlas(slot, uv);
     let sd_rgb = median3(mtsdf.r, mtsdf.g, mtsdf.b);
     let sd_a = mtsdf.a;
     return max(sd_rgb, sd_a);
 }
 
+// Coverage of an edge at signed distance d in screen pixels, softened outwards by softness
+fn edgeCoverage(d: f32, softness: f32) -> f32 {
+    return smoothstep(-0.5 - softness, 0.5, d);
+}
+
+// Layers glow, outline and fill from the distance to the glyph edge. Outline and glow use the
+// true distance in the alpha channel, which stays accurate away from the edge where the
+// median of RGB does not. Each layer only covers what the layer above leaves, so text with a
+// transparent fill draws as a hollow outline.
+fn styledText(style_index: u32, color: vec4<f32>, true_sd: f32, px_range: f32, fill_dist: f32) -> vec4<f32> {
+    let style = text_styles[min(style_index, 15u)];
+    let softness = max(style.params.z, 0.0);
+
+    let true_dist = px_range * (true_sd - 0.5);
+    let fill = edgeCoverage(select(fill_dist, true_dist, softness > 0.0), softness);
+    let outer = max(edgeCoverage(true_dist + style.params.x, softness), fill);
+    var glow = 0.0;
+    if style.params.y > 0.0 {
+        glow = 1.0 - smoothstep(0.0, style.params.y, -(true_dist + style.params.x));
+    }
+
+    // Composite front to back with premultiplied alpha
+    let fill_a = color.a * fill;
+    let outline_a = style.outline_color.a * (outer - fill);
+    let glow_a = style.glow_color.a * glow * (1.0 - outer);
+    var result = vec4<f32>(color.rgb * fill_a, fill_a);
+    result += vec4<f32>(style.outline_color.rgb * outline_a, outline_a) * (1.0 - result.a);
+    result += vec4<f32>(style.glow_color.rgb * glow_a, glow_a) * (1.0 - result.a);
+    if result.a <= 0.0 {
+        return vec4<f32>(0.0);
+    }
+    return vec4<f32>(result.rgb / result.a, result.a);
+}
+
 @fragment
 fn fs_main(
     @location(0) local_pos: vec2<f32>,
@@ -205,22 +268,25 @@ fn fs_main(
     @location(3) color: vec4<f32>,
     @location(4) tex_coords: vec2<f32>,
     @location(5) half_size: vec2<f32>,
+    @location(6) @interpolate(flat) atlas_slot: u32,
 ) -> @location(0) vec4<f32> {
     var output_color: vec4<f32> = vec4<f32>(0.0, 0.0, 0.0, 0.0);
 
+    let msdf_params = u_msdf_params[atlas_slot];
+
     // MTSDF rendering with 4x supersampling for better quality on low-DPI displays
-    let msdf_pxRange = screenPxRange(tex_coords);
+    let msdf_pxRange = screenPxRange(msdf_params, tex_coords);
 
     // Calculate texel size for supersampling offsets
-    let tex_size = vec2<f32>(u_msdf_params.y, u_msdf_params.z);
+    let tex_size = vec2<f32>(msdf_params.y, msdf_params.z);
     let texelSize = 1.0 / tex_size;
 
     // 4x rotated grid supersampling (reduces aliasing better than regular grid)
     let offset = texelSize * 0.375;
-    let sd0 = sampleMSDF(tex_coords + vec2<f32>(-offset.x, -offset.y * 0.5));
-    let sd1 = sampleMSDF(tex_coords + vec2<f32>(offset.x, -offset.y * 0.5));
-    let sd2 = sampleMSDF(tex_coords + vec2<f32>(-offset.x * 0.5, offset.y));
-    let sd3 = sampleMSDF(tex_coords + vec2<f32>(offset.x * 0.5, offset.y));
+    let sd0 = sampleMSDF(atlas_slot, tex_coords + vec2<f32>(-offset.x, -offset.y * 0.5));
+    let sd1 = sampleMSDF(atlas_slot, tex_coords + vec2<f32>(offset.x, -offset.y * 0.5));
+    let sd2 = sampleMSDF(atlas_slot, tex_coords + vec2<f32>(-offset.x * 0.5, offset.y));
+    let sd3 = sampleMSDF(atlas_slot, tex_coords + vec2<f32>(offset.x * 0.5, offset.y));
 
     // Average the samples
     let sd = (sd0 + sd1 + sd2 + sd3) * 0.25;
@@ -230,8 +296,8 @@ fn fs_main(
     let msdf_opacity_rgb = clamp(screenPxDist + 0.5, 0.0, 1.0);
 
     // Alpha-only opacity for fallback mode (single sample is fine for fallback)
-    let mtsdf_a = textureSample(t_msdf_atlas, s_msdf_atlas, tex_coords);
-    let sd_a = mtsdf_a.a;
+    let mtsdf = sampleAtlas(atlas_slot, tex_coords);
+    let sd_a = mtsdf.a;
     let pxDist_a = msdf_pxRange * (sd_a - 0.5);
     let msdf_opacity_a = clamp(pxDist_a + 0.5, 0.0, 1.0);
 
@@ -281,7 +347,14 @@ fn fs_main(
         // Mode 6: sharp mode for small text
         // Mode 7: soft mode for large text
         // Mode 8: crisp mode - ultra tight AA for small text
-        let msdf_mode = u_msdf_params.w;
+        let msdf_mode = msdf_params.w;
+
+        let style_index = u32(radius + 0.5);
+        if style_index > 0u {
+            let styled = styledText(style_index, color, sd_a, msdf_pxRange, screenPxDist);
+            if styled.a < 0.005 { discard; }
+            return styled;
+        }
 
         // Simplified mode handling (banana-c style - single sample is sufficient)
         if msdf_mode >= 2.5 {
@@ -0,0 +1,95 @@
+//go:build !js
+
+package window
+
+import (
+	"image"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+	"github.com/go-gl/glfw/v3.3/glfw"
+)
+
+// SetWindowIcon sets the icon of the window from images of different sizes; the
+// system picks the size closest to what it needs. No images restores the default icon.
+func (w *Window) SetWindowIcon(images []image.Image) {
+	w.Window.SetIcon(images)
+}
+
+// SetWindowPosition moves the top left corner of the window's content area to (x, y)
+// in screen coordinates.
+func (w *Window) SetWindowPosition(x, y int) {
+	w.Window.SetPos(x, y)
+}
+
+// SetWindowSizeLimits limits the size the user can resize the window to. Zero leaves a
+// limit unset.
+func (w *Window) SetWindowSizeLimits(minWidth, minHeight, maxWidth, maxHeight int) {
+	limit := func(v int) int {
+		if v <= 0 {
+			return glfw.DontCare
+		}
+		return v
+	}
+	w.Window.SetSizeLimits(limit(minWidth), limit(minHeight), limit(maxWidth), limit(maxHeight))
+}
+
+// SetAlwaysOnTop keeps the window above other windows.
+func (w *Window) SetAlwaysOnTop(v bool) {
+	value := glfw.False
+	if v {
+		value = glfw.True
+	}
+	w.Window.SetAttrib(glfw.Floating, value)
+}
+
+func (w *Window) MinimizeWindow() {
+	w.Window.Iconify()
+}
+
+func (w *Window) MaximizeWindow() {
+	w.Window.Maximize()
+}
+
+// RestoreWindow restores a minimized or maximized window to its previous size.
+func (w *Window) RestoreWindow() {
+	w.Window.Restore()
+}
+
+// RequestAttention asks the system to highlight the window, such as by flashing its
+// taskbar entry, without taking focus.
+func (w *Window) RequestAttention() {
+	w.Window.RequestAttention()
+}
+
+// setWindowStateCallbacks sends events for moving, minimizing, maximizing, restoring and
+// focusing the window.
+func (w *Window) setWindowStateCallbacks(fn func(eventChan chan input.Event)) {
+	send := func(evt input.Event) {
+		w.eventChan <- evt
+		fn(w.eventChan)
+	}
+	w.Window.SetPosCallback(func(window *glfw.Window, x, y int) {
+		send(input.Event{Type: input.WindowMoved, X: x, Y: y})
+	})
+	w.Window.SetIconifyCallback(func(window *glfw.Window, iconified bool) {
+		if iconified {
+			send(input.Event{Type: input.WindowMinimized})
+		} else {
+			send(input.Event{Type: input.WindowRestored})
+		}
+	})
+	w.Window.SetMaximizeCallback(func(window *glfw.Window, maximized bool) {
+		if maximized {
+			send(input.Event{Type: input.WindowMaximized})
+		} else {
+			send(input.Event{Type: input.WindowRestored})
+		}
+	})
+	w.Window.SetFocusCallback(func(window *glfw.Window, focused bool) {
+		if focused {
+			send(input.Event{Type: input.WindowFocused})
+		} else {
+			send(input.Event{Type: input.WindowUnfocused})
+		}
+	})
+}
@@ -3,6 +3,9 @@
 package window
 
 import (
+	"path/filepath"
+
+	"github.com/dfirebaugh/hlg/graphics"
 	"github.com/dfirebaugh/hlg/pkg/input"
 	"github.com/go-gl/glfw/v3.3/glfw"
 	"github.com/rajveermalviya/go-webgpu/wgpu"
@@ -15,6 +18,16 @@ type Window struct {
 	isDisposed                  bool
 	targetWidth, targetHeight   int // logical size set by SetWindowSize
 	currentWidth, currentHeight int // actual window size
+
+	cursors                  map[glfw.StandardCursor]*glfw.Cursor
+	customCursor             *glfw.Cursor
+	lastCursorX, lastCursorY float64 // window position of the last cursor move
+	hasLastCursor            bool
+
+	// Geometry of the window before it went fullscreen
+	windowedX, windowedY int
+	windowedW, windowedH int
+	hasWindowed          bool
 }
 
 func NewWindow(width, height int) (*Window, error) {
@@ -49,12 +62,9 @@ func (w *Window) GetSurfaceDescriptor() *wgpu.SurfaceDescriptor {
 
 func (w *Window) SetBorderlessWindowed(v bool) {
 	if v {
-		monitor := glfw.GetPrimaryMonitor()
-		mode := monitor.GetVideoMode()
-		w.Window.SetMonitor(monitor, 0, 0, mode.Width, mode.Height, mode.RefreshRate)
+		w.SetFullscreen(-1, graphics.VideoMode{})
 	} else {
-		// Restore window to windowed mode with a default size and position
-		w.Window.SetMonitor(nil, 100, 100, 800, 600, 0)
+		w.SetWindowed()
 	}
 }
 
@@ -88,25 +98,34 @@ func (w *Window) SetInputCallback(fn func(eventChan chan input.Event)) {
 		} else if action == glfw.Release {
 			eventType = input.MouseRelease
 		}
-		w.eventChan <- input.Event{Type: eventType, MouseButton: input.MouseButton(button)}
+		w.eventChan <- input.Event{Type: eventType, MouseButton: input.MouseButton(button), Mods: input.ModifierKey(mods)}
 		fn(w.eventChan)
 	})
 
 	w.Window.SetCursorPosCallback(func(window *glfw.Window, xpos, ypos float64) {
 		// Translate from current window coordinates to logical coordinates
 		x, y := w.windowToLogical(xpos, ypos)
-		w.eventChan <- input.Event{Type: input.MouseMove, X: x, Y: y}
+		var dx, dy float64
+		if w.hasLastCursor {
+			dx, dy = xpos-w.lastCursorX, ypos-w.lastCursorY
+		}
+		w.lastCursorX, w.lastCursorY, w.hasLastCursor = xpos, ypos, true
+		w.eventChan <- input.Event{Type: input.MouseMove, X: x, Y: y, DX: dx, DY: dy}
 		fn(w.eventChan)
 	})
 
 	w.Window.SetKeyCallback(func(window *glfw.Window, key glfw.Key, scancode int, action glfw.Action, mods glfw.ModifierKey) {
-		var eventType input.EventType
-		if action == glfw.Press {
-			eventType = input.KeyPress
-		} else if action == glfw.Release {
+		eventType := input.KeyPress
+		if action == glfw.Release {
 			eventType = input.KeyRelease
 		}
-		w.eventChan <- input.Event{Type: eventType, Key: input.Key(key)}
+		w.eventChan <- input.Event{
+			Type:     eventType,
+			Key:      input.Key(key),
+			Mods:     input.ModifierKey(mods),
+			Scancode: scancode,
+			Repeat:   action == glfw.Repeat,
+		}
 		fn(w.eventChan)
 	})
 
@@ -114,6 +133,22 @@ func (w *Window) SetInputCallback(fn func(eventChan chan input.Event)) {
 		w.eventChan <- input.Event{Type: input.CharInput, Rune: char}
 		fn(w.eventChan)
 	})
+
+	w.Window.SetScrollCallback(func(window *glfw.Window, xoff, yoff float64) {
+		w.eventChan <- input.Event{Type: input.MouseScroll, ScrollX: xoff, ScrollY: yoff}
+		fn(w.eventChan)
+	})
+
+	w.Window.SetDropCallback(func(window *glfw.Window, names []string) {
+		files := make([]input.DroppedFile, len(names))
+		for i, path := range names {
+			files[i] = input.DroppedFile{Name: filepath.Base(path), Path: path}
+		}
+		w.eventChan <- input.Event{Type: input.FilesDropped, Files: files}
+		fn(w.eventChan)
+	})
+
+	w.setWindowStateCallbacks(fn)
 }
 
 func (w *Window) SetResizedCallback(fn func(physicalWidth, physicalHeight uint32)) {
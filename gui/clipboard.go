@@ -0,0 +1,28 @@
+package gui
+
+// Clipboard reads and writes the text copied, cut and pasted in text widgets.
+// When the InputContext passed to NewContext implements Clipboard, text widgets use it;
+// otherwise copied text is kept in the Context. Use SetClipboard to replace it.
+type Clipboard interface {
+	GetClipboardText() string
+	SetClipboardText(text string)
+}
+
+// memoryClipboard keeps copied text in memory, for input contexts without access to
+// the system clipboard.
+type memoryClipboard struct {
+	text string
+}
+
+func (m *memoryClipboard) GetClipboardText() string {
+	return m.text
+}
+
+func (m *memoryClipboard) SetClipboardText(text string) {
+	m.text = text
+}
+
+// SetClipboard sets the clipboard text widgets copy to and paste from.
+func (c *Context) SetClipboard(cb Clipboard) {
+	c.clipboard = cb
+}
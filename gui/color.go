@@ -0,0 +1,213 @@
+package gui
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+const (
+	colorBarSize = 14 // width of the hue bar and height of the alpha bar
+	colorSteps   = 24 // cells per side of the saturation-value square
+	hueSteps     = 36 // bands of the hue bar
+	alphaSteps   = 16 // bands of the alpha bar
+)
+
+// ColorEdit renders a color picker filling the given rectangle and returns true if
+// the color changed: a saturation-value square with a hue bar to its right, an alpha
+// bar below them, and a swatch next to a hex input at the bottom. The hex input takes
+// "#rrggbb" or "#rrggbbaa". Alpha is stored unpremultiplied, as in Style colors.
+// The label is used for widget identification (not displayed).
+func (c *Context) ColorEdit(label string, col *color.RGBA, x, y, w, h int) bool {
+	id := c.GetID(label + "_color")
+	gap := c.style.Spacing
+	hexH := c.style.ItemHeight
+	svW := max(w-colorBarSize-gap, 0)
+	svH := max(h-hexH-colorBarSize-2*gap, 0)
+	hueX := x + svW + gap
+	alphaY := y + svH + gap
+	hexY := alphaY + colorBarSize + gap
+
+	// Hue and saturation are lost in gray and black, so keep the ones picked last
+	hue, sat, val := rgbToHSV(*col)
+	if c.colorEditID == id && c.colorEditLast == *col {
+		hue, sat, val = c.colorEditHSV[0], c.colorEditHSV[1], c.colorEditHSV[2]
+	}
+	alpha := float32(col.A) / 255
+	old := *col
+
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my)
+	fx := func(x0, w int) float32 { return min(max(float32(mx-x0)/float32(max(w, 1)), 0), 1) }
+	fy := func(y0, h int) float32 { return min(max(float32(my-y0)/float32(max(h, 1)), 0), 1) }
+
+	svID, hueID, alphaID := c.GetID(label+"_color_sv"), c.GetID(label+"_color_hue"), c.GetID(label+"_color_alpha")
+	picking := false
+	for _, part := range []struct {
+		id         ID
+		x, y, w, h int
+	}{
+		{svID, x, y, svW, svH},
+		{hueID, hueX, y, colorBarSize, svH},
+		{alphaID, x, alphaY, w, colorBarSize},
+	} {
+		if hovered && pointInRect(mx, my, part.x, part.y, part.w, part.h) {
+			c.setHot(part.id)
+			if c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+				c.setActive(part.id)
+			}
+		}
+		if !c.isActive(part.id) {
+			continue
+		}
+		if !c.input.IsButtonPressed(input.MouseButtonLeft) {
+			c.clearActive()
+			continue
+		}
+		picking = true
+		switch part.id {
+		case svID:
+			sat, val = fx(x, svW), 1-fy(y, svH)
+		case hueID:
+			hue = fy(y, svH)
+		case alphaID:
+			alpha = fx(x, w)
+		}
+	}
+	if picking {
+		*col = hsvToRGB(hue, sat, val)
+		col.A = uint8(math.Round(float64(alpha) * 255))
+	}
+
+	// Saturation-value square, drawn as a grid of cells
+	for i := 0; i < colorSteps; i++ {
+		x0, x1 := x+i*svW/colorSteps, x+(i+1)*svW/colorSteps
+		s := (float32(i) + 0.5) / colorSteps
+		for j := 0; j < colorSteps; j++ {
+			y0, y1 := y+j*svH/colorSteps, y+(j+1)*svH/colorSteps
+			c.filledRect(x0, y0, x1-x0, y1-y0, hsvToRGB(hue, s, 1-(float32(j)+0.5)/colorSteps))
+		}
+	}
+	markerX, markerY := x+int(sat*float32(svW)), y+int((1-val)*float32(svH))
+	opaque := hsvToRGB(hue, sat, val)
+	c.roundedRectOutline(markerX-5, markerY-5, 10, 10, 5, 2, opaque, c.color(ColorText))
+
+	// Hue bar
+	for i := 0; i < hueSteps; i++ {
+		y0, y1 := y+i*svH/hueSteps, y+(i+1)*svH/hueSteps
+		c.filledRect(hueX, y0, colorBarSize, y1-y0, hsvToRGB((float32(i)+0.5)/hueSteps, 1, 1))
+	}
+	c.filledRect(hueX-2, y+int(hue*float32(svH))-1, colorBarSize+4, 3, c.color(ColorText))
+
+	// Alpha bar over a checkerboard
+	c.checkerboard(x, alphaY, w, colorBarSize)
+	for i := 0; i < alphaSteps; i++ {
+		x0, x1 := x+i*w/alphaSteps, x+(i+1)*w/alphaSteps
+		band := opaque
+		band.A = uint8((float32(i) + 0.5) / alphaSteps * 255)
+		c.filledRect(x0, alphaY, x1-x0, colorBarSize, band)
+	}
+	c.filledRect(x+int(alpha*float32(w))-1, alphaY-2, 3, colorBarSize+4, c.color(ColorText))
+
+	// Swatch and hex input
+	c.checkerboard(x, hexY, hexH, hexH)
+	c.filledRect(x, hexY, hexH, hexH, *col)
+
+	hexLabel := label + "_color_hex"
+	hexID := c.GetID(hexLabel + "_input")
+	if c.isFocused(hexID) {
+		if c.hexEditID != hexID {
+			c.hexEditID = hexID
+			c.hexEditText = formatHexColor(*col)
+			c.hexEditState = TextInputState{CursorPos: len(c.hexEditText)}
+		}
+		if changed, _ := c.InputText(hexLabel, &c.hexEditText, &c.hexEditState, x+hexH+gap, hexY, w-hexH-gap, hexH); changed {
+			if parsed, err := parseHexColor(c.hexEditText); err == nil {
+				*col = parsed
+				hue, sat, val = rgbToHSV(parsed)
+			}
+		}
+	} else {
+		if c.hexEditID == hexID {
+			c.hexEditID = 0
+		}
+		text := formatHexColor(*col)
+		var state TextInputState
+		c.InputText(hexLabel, &text, &state, x+hexH+gap, hexY, w-hexH-gap, hexH)
+	}
+
+	if *col != old || picking {
+		c.colorEditID = id
+		c.colorEditLast = *col
+		c.colorEditHSV = [3]float32{hue, sat, val}
+	}
+	return *col != old
+}
+
+// checkerboard draws the gray checks transparent colors are shown over.
+func (c *Context) checkerboard(x, y, w, h int) {
+	const check = colorBarSize / 2
+	light, dark := color.RGBA{R: 200, G: 200, B: 200, A: 255}, color.RGBA{R: 130, G: 130, B: 130, A: 255}
+	for cy := 0; cy < h; cy += check {
+		for cx := 0; cx < w; cx += check {
+			col := light
+			if (cx/check+cy/check)%2 == 1 {
+				col = dark
+			}
+			c.filledRect(x+cx, y+cy, min(check, w-cx), min(check, h-cy), col)
+		}
+	}
+}
+
+// rgbToHSV returns the hue, saturation and value of a color, each from 0 to 1.
+func rgbToHSV(col color.RGBA) (h, s, v float32) {
+	r, g, b := float32(col.R)/255, float32(col.G)/255, float32(col.B)/255
+	maxC, minC := max(r, g, b), min(r, g, b)
+	v = maxC
+	d := maxC - minC
+	if maxC > 0 {
+		s = d / maxC
+	}
+	if d == 0 {
+		return 0, s, v
+	}
+	switch maxC {
+	case r:
+		h = (g - b) / d
+		if h < 0 {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h / 6, s, v
+}
+
+// hsvToRGB returns the opaque color with the given hue, saturation and value, each
+// from 0 to 1.
+func hsvToRGB(h, s, v float32) color.RGBA {
+	h = float32(math.Mod(float64(h)*6, 6))
+	i := int(h)
+	f := h - float32(i)
+	p, q, t := v*(1-s), v*(1-s*f), v*(1-s*(1-f))
+	var r, g, b float32
+	switch i {
+	case 0:
+		r, g, b = v, t, p
+	case 1:
+		r, g, b = q, v, p
+	case 2:
+		r, g, b = p, v, t
+	case 3:
+		r, g, b = p, q, v
+	case 4:
+		r, g, b = t, p, v
+	default:
+		r, g, b = v, p, q
+	}
+	to8 := func(x float32) uint8 { return uint8(math.Round(float64(x) * 255)) }
+	return color.RGBA{R: to8(r), G: to8(g), B: to8(b), A: 255}
+}
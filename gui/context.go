@@ -5,7 +5,6 @@ import (
 	"image/color"
 	"time"
 
-	"github.com/dfirebaugh/hlg"
 	"github.com/dfirebaugh/hlg/pkg/input"
 )
 
@@ -20,7 +19,12 @@ type InputContext interface {
 	IsButtonJustReleased(button input.MouseButton) bool
 	IsKeyPressed(key input.Key) bool
 	IsKeyJustPressed(key input.Key) bool
+	// IsKeyRepeated returns true if a held key repeated this frame. Text editing acts on
+	// repeats as on presses, so held arrows and Backspace keep going.
+	IsKeyRepeated(key input.Key) bool
 	GetCharInput() []rune
+	// ScrollDelta returns the mouse wheel movement this frame. Positive y scrolls up.
+	ScrollDelta() (x, y float64)
 	Update()
 }
 
@@ -28,11 +32,13 @@ type InputContext interface {
 type panelBound struct {
 	id         ID
 	x, y, w, h int
+	popup      bool // popups block every widget beneath them, not only panels below them
 }
 
 // Context holds the state for an immediate mode GUI frame.
 type Context struct {
 	input      InputContext
+	renderer   Renderer
 	hotID      ID // Widget being hovered
 	activeID   ID // Widget being interacted with
 	focusedID  ID // Widget receiving keyboard input
@@ -40,20 +46,117 @@ type Context struct {
 	frameTime  time.Time
 	focusables []ID // List of focusable widgets in render order
 	tabPressed bool
+	escapeUsed bool // a popup closed on escape this frame
 	shiftHeld  bool
+	ctrlHeld   bool
 
 	// Panel input blocking (uses previous frame's bounds)
 	panelBounds     []panelBound // Current frame's panel bounds
 	prevPanelBounds []panelBound // Previous frame's panel bounds (used for blocking)
 	currentPanelID  ID           // The panel currently being rendered into (for widget blocking)
+
+	// Panel stacking and tab groups
+	panelOrder         []ID        // panels from back to front
+	panels             []panelInfo // panels drawn this frame
+	prevPanels         []panelInfo // panels drawn last frame
+	tabDragID          ID          // panel whose tab is pressed, detached once dragged far enough
+	tabDragX, tabDragY int
+
+	// Layout stack for widgets added without coordinates
+	layouts   []*layout
+	nextWidth *Size
+
+	// Clip rects of open scroll regions, intersected with their parents
+	clipRects [][4]int
+
+	// Mouse wheel movement not yet consumed by a scroll region this frame
+	wheelX, wheelY float64
+
+	// Where text widgets copy to and paste from
+	clipboard Clipboard
+
+	// Cursor shape requested by the widget under the mouse, and the shape shown
+	cursorSetter CursorSetter
+	cursorShape  input.CursorShape
+	shownCursor  input.CursorShape
+
+	// Drawing deferred to the end of the frame so it appears above every widget
+	overlays []func()
+
+	// Combo popup state (only one popup is open at a time)
+	popupID     ID   // combo whose popup is open
+	popupSeen   bool // the open combo was drawn this frame
+	popupCursor int  // item highlighted by the arrow keys
+	popupScroll ScrollState
+	pickedID    ID  // combo whose popup item was clicked, applied on its next call
+	pickedItem  int // the clicked item
+
+	// Menus share popupID with combos, so opening one closes the other
+	menuBar      *menuBar
+	menu         *menu // the menu being filled between Menu and EndMenu
+	menuFromBar  bool  // the open menu belongs to a menu bar
+	menuOpened   bool  // the open menu was opened this frame
+	menuX, menuY int   // where the open context menu was opened
+	menuW, menuH int   // size of the open menu last frame
+
+	// Modal popup state (only one modal is open at a time)
+	modalID                      ID
+	inModal                      bool // between BeginPopupModal and EndPopupModal
+	modalLayout                  *layout
+	modalContentW, modalContentH int
+
+	// Tooltip of the last widget, see Tooltip
+	lastItemID   ID // the last widget that registered for focus
+	tooltipID    ID // the widget the mouse rests on
+	tooltipStart time.Time
+	tooltipSeen  bool
+	tooltipText  string
+
+	// Tables and tab bars opened and not yet closed
+	tables  []*table
+	tabBars []*tabBar
+
+	// Drawing deferred to the end of the frame, see drawLayer
+	layers     []*drawLayer
+	layerStack []*drawLayer
+
+	// Drag field being typed into and the drag in progress
+	dragEditID     ID
+	dragEditText   string
+	dragEditState  TextInputState
+	dragStartX     int
+	dragStartValue float64
+
+	// Hue, saturation and value of the color edit changed last, kept while its color is
+	// unchanged so gray and black do not lose them
+	colorEditID   ID
+	colorEditHSV  [3]float32
+	colorEditLast color.RGBA
+
+	// Hex input of the color edit being typed into
+	hexEditID    ID
+	hexEditText  string
+	hexEditState TextInputState
+
+	style      Style
+	styleStack []Style // styles replaced by PushStyle and PushStyleColor
 }
 
 // NewContext creates a new gui context.
 func NewContext(input InputContext) *Context {
-	return &Context{
-		input:   input,
-		idStack: make([]ID, 0, 8),
+	c := &Context{
+		input:    input,
+		renderer: DefaultRenderer{},
+		idStack:  make([]ID, 0, 8),
+		style:    DarkStyle(),
+	}
+	if cb, ok := input.(Clipboard); ok {
+		c.clipboard = cb
+	} else {
+		c.clipboard = &memoryClipboard{}
 	}
+	c.cursorSetter, _ = input.(CursorSetter)
+	return c
 }
 
 // Begin starts a new immediate mode frame.
@@ -61,22 +164,46 @@ func (c *Context) Begin() {
 	c.frameTime = time.Now()
 	c.hotID = 0
 	c.focusables = c.focusables[:0]
+	c.cursorShape = input.CursorArrow
 
-	// Swap panel bounds for input blocking (use previous frame's data)
-	c.prevPanelBounds = c.panelBounds
-	c.panelBounds = c.panelBounds[:0]
+	// Swap panel bounds for input blocking (use previous frame's data).
+	// The buffers are swapped so this frame's bounds do not overwrite last frame's.
+	c.prevPanelBounds, c.panelBounds = c.panelBounds, c.prevPanelBounds[:0]
+	c.prevPanels, c.panels = c.panels, c.prevPanels[:0]
 	c.currentPanelID = 0
+	c.layouts = c.layouts[:0]
+	c.nextWidth = nil
+	c.wheelX, c.wheelY = c.input.ScrollDelta()
 
 	// Handle tab navigation
 	c.tabPressed = c.input.IsKeyJustPressed(input.KeyTab)
+	c.escapeUsed = false
 	c.shiftHeld = c.input.IsKeyPressed(input.KeyLeftShift) || c.input.IsKeyPressed(input.KeyRightShift)
+	c.ctrlHeld = c.input.IsKeyPressed(input.KeyLeftControl) || c.input.IsKeyPressed(input.KeyRightControl)
 
 	// Start batched drawing
-	hlg.BeginDraw()
+	c.renderer.BeginDraw()
 }
 
 // End finishes the immediate mode frame.
 func (c *Context) End() {
+	c.EndPanel()
+	for len(c.layerStack) > 0 {
+		c.endLayer() // modal popups left open
+	}
+	c.inModal = false
+	c.menu, c.menuBar = nil, nil
+	c.layouts = c.layouts[:0]
+	c.tables = c.tables[:0]
+	c.tabBars = c.tabBars[:0]
+	for len(c.clipRects) > 0 {
+		c.popClip() // scroll regions left open
+	}
+	c.prunePanelOrder()
+	c.drawLayers()
+	c.drawOverlays()
+	c.drawTooltip()
+
 	// Handle tab navigation after all widgets have registered
 	if c.tabPressed && len(c.focusables) > 0 {
 		c.handleTabNavigation()
@@ -87,8 +214,10 @@ func (c *Context) End() {
 		c.focusedID = 0
 	}
 
+	c.applyCursor()
+
 	// Submit batched drawing
-	hlg.EndDraw()
+	c.renderer.EndDraw()
 }
 
 // handleTabNavigation moves focus between widgets.
@@ -124,6 +253,10 @@ func (c *Context) handleTabNavigation() {
 
 // registerFocusable adds a widget to the tab order.
 func (c *Context) registerFocusable(id ID) {
+	c.lastItemID = id
+	if c.modalID != 0 && !c.inModal {
+		return // the open modal popup keeps focus
+	}
 	c.focusables = append(c.focusables, id)
 }
 
@@ -193,17 +326,17 @@ func pointInRect(px, py, x, y, w, h int) bool {
 
 // Label renders static text at the given position.
 func (c *Context) Label(text string, x, y int) {
-	hlg.Text(text, x, y, 14, color.RGBA{220, 220, 220, 255})
+	c.text(text, x, y, c.style.FontSize, c.color(ColorText))
 }
 
 // LabelWithSize renders text at the given position with a specific font size.
 func (c *Context) LabelWithSize(text string, x, y int, size float32) {
-	hlg.Text(text, x, y, size, color.RGBA{220, 220, 220, 255})
+	c.text(text, x, y, size, c.color(ColorText))
 }
 
 // LabelWithColor renders text at the given position with a specific color.
 func (c *Context) LabelWithColor(text string, x, y int, col color.Color) {
-	hlg.Text(text, x, y, 14, col)
+	c.text(text, x, y, c.style.FontSize, col)
 }
 
 // registerPanelBounds records a panel's bounds for input blocking.
@@ -211,16 +344,16 @@ func (c *Context) registerPanelBounds(id ID, x, y, w, h int) {
 	c.panelBounds = append(c.panelBounds, panelBound{id: id, x: x, y: y, w: w, h: h})
 }
 
-// isBlockedByLaterPanel checks if a point would be blocked by a panel
-// rendered after the given panel ID (higher z-order).
-func (c *Context) isBlockedByLaterPanel(id ID, px, py int) bool {
-	foundSelf := false
+// isBlockedByPanelAbove checks if a point would be blocked by a popup or by a panel
+// above the given panel ID in the z-order. Every panel is above widgets outside panels,
+// which pass 0.
+func (c *Context) isBlockedByPanelAbove(id ID, px, py int) bool {
+	z := c.panelZ(id)
 	for _, pb := range c.prevPanelBounds {
-		if pb.id == id {
-			foundSelf = true
+		if pb.id == id || !pointInRect(px, py, pb.x, pb.y, pb.w, pb.h) {
 			continue
 		}
-		if foundSelf && pointInRect(px, py, pb.x, pb.y, pb.w, pb.h) {
+		if pb.popup || c.panelZ(pb.id) > z {
 			return true
 		}
 	}
@@ -228,12 +361,32 @@ func (c *Context) isBlockedByLaterPanel(id ID, px, py int) bool {
 }
 
 // isInputBlocked checks if input at the given point should be blocked.
-// This is used by widgets to check if they're covered by a later panel.
+// This is used by widgets to check if they're covered by a panel above them.
 func (c *Context) isInputBlocked(px, py int) bool {
-	if c.currentPanelID == 0 {
-		return false // Not inside a panel
+	if len(c.clipRects) > 0 {
+		r := c.clipRects[len(c.clipRects)-1]
+		if !pointInRect(px, py, r[0], r[1], r[2], r[3]) {
+			return true // Scrolled out of view
+		}
+	}
+	// Popups block every widget beneath them: the widgets outside popups and those of
+	// popups opened before them
+	inPopup := -1
+	for i, pb := range c.prevPanelBounds {
+		if pb.popup && pb.id == c.currentPanelID {
+			inPopup = i
+			break
+		}
+	}
+	for i, pb := range c.prevPanelBounds {
+		if pb.popup && i > inPopup && pb.id != c.currentPanelID && pointInRect(px, py, pb.x, pb.y, pb.w, pb.h) {
+			return true
+		}
+	}
+	if inPopup >= 0 {
+		return false
 	}
-	return c.isBlockedByLaterPanel(c.currentPanelID, px, py)
+	return c.isBlockedByPanelAbove(c.currentPanelID, px, py)
 }
 
 // SetCurrentPanel sets the current panel context for widget input blocking.
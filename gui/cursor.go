@@ -0,0 +1,25 @@
+package gui
+
+import "github.com/dfirebaugh/hlg/pkg/input"
+
+// CursorSetter changes the shape of the mouse cursor. When the InputContext passed to
+// NewContext implements CursorSetter, the cursor becomes an I-beam over text fields
+// and resize arrows over panel edges and table column edges.
+type CursorSetter interface {
+	SetCursorShape(shape input.CursorShape)
+}
+
+// setCursor shows shape as the cursor at the end of the frame.
+func (c *Context) setCursor(shape input.CursorShape) {
+	c.cursorShape = shape
+}
+
+// applyCursor sets the cursor requested this frame, or the arrow if none was, when it
+// differs from the cursor shown.
+func (c *Context) applyCursor() {
+	if c.cursorSetter == nil || c.cursorShape == c.shownCursor {
+		return
+	}
+	c.cursorSetter.SetCursorShape(c.cursorShape)
+	c.shownCursor = c.cursorShape
+}
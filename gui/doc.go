@@ -21,4 +21,59 @@
 //	}
 //
 //	ctx.End()
+//
+// Widgets can also be placed by a layout instead of absolute coordinates.
+// The Add* variants flow down the body of the last Panel, or down the screen
+// outside of panels, and BeginRow/BeginColumn nest rows and columns:
+//
+//	if ctx.Panel("Settings", &panelState, 0, 0) { // 0 sizes the panel to its content
+//	    ctx.BeginRow(gui.Percent(30), gui.Fill())
+//	    ctx.AddLabel("Volume")
+//	    ctx.AddSlider("vol", &volume, 0, 1)
+//	    ctx.EndRow()
+//
+//	    if ctx.AddButton("Apply") {
+//	        apply()
+//	    }
+//	    ctx.SameLine()
+//	    ctx.AddButton("Cancel")
+//	}
+//
+// Tool UIs can group widgets with AddTreeNode and TreePop, TabBar and TabItem, and
+// show rows of data with BeginTable, TableNextRow, TableNextColumn and EndTable:
+//
+//	if ctx.AddTreeNode("Scene", &sceneOpen) {
+//	    for _, e := range entities {
+//	        if ctx.AddSelectable(e.Name, e == selected) {
+//	            selected = e
+//	        }
+//	    }
+//	    ctx.TreePop()
+//	}
+//
+// Menus, modal popups and tooltips are drawn above the other widgets at End, whatever
+// the order they were added in. See BeginMenuBar, BeginContextMenu, BeginPopupModal
+// and Tooltip.
+//
+// Panels are drawn above widgets outside panels, and a clicked panel comes to the front.
+// Users can resize panels, dock them into screen edges and drop them on each other to
+// group them as tabs. Keep the states in a map keyed by label to save and restore the
+// arrangement:
+//
+//	panels := map[string]*gui.PanelState{"Scene": {}, "Inspector": {X: 400}}
+//	gui.LoadPanelLayoutFile("layout.json", panels)
+//	defer gui.SavePanelLayoutFile("layout.json", panels)
+//
+// A Context draws through a Renderer, hlg unless SetRenderer replaces it. Package
+// guitest runs widgets on scripted input with a renderer that records the draw calls,
+// so widgets can be tested without a window.
+//
+// Colors, corner radii, font and spacing come from a Style. Start from one of the
+// presets or load one from JSON, and override colors for a few widgets with
+// PushStyleColor and PopStyle:
+//
+//	ctx.SetStyle(gui.LightStyle())
+//	ctx.PushStyleColor(gui.ColorButton, colornames.Darkred)
+//	ctx.AddButton("Delete")
+//	ctx.PopStyle()
 package gui
@@ -0,0 +1,167 @@
+package gui
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// DragFloat renders a number field that changes *value by speed for every pixel the
+// mouse is dragged horizontally, and returns true if the value changed.
+// Ctrl-clicking the field, or pressing enter while it has focus, turns it into a text
+// input to type an exact value: enter or clicking elsewhere applies it and escape cancels.
+// While it has focus, the left and right keys step the value by speed.
+// The value is kept within [min, max] unless min and max are equal.
+// The label is used for widget identification (not displayed).
+func (c *Context) DragFloat(label string, value *float32, speed, min, max float32, x, y, w, h int) bool {
+	v := float64(*value)
+	changed := c.dragNumber(label, &v, float64(speed), float64(speed), float64(min), float64(max), 3, x, y, w, h)
+	if changed {
+		*value = float32(v)
+	}
+	return changed
+}
+
+// DragInt renders a number field like DragFloat for an integer. speed is the change per
+// pixel dragged, so a speed of 0.1 changes the value by one for every ten pixels; the
+// left and right keys step the value by one.
+func (c *Context) DragInt(label string, value *int, speed float32, min, max int, x, y, w, h int) bool {
+	v := float64(*value)
+	changed := c.dragNumber(label, &v, float64(speed), 1, float64(min), float64(max), 0, x, y, w, h)
+	if changed {
+		*value = int(v)
+	}
+	return changed
+}
+
+// dragNumber implements DragFloat and DragInt for a value shown with the given number of
+// decimals. Values are rounded to the decimals shown.
+func (c *Context) dragNumber(label string, value *float64, speed, step, min, max float64, decimals int, x, y, w, h int) bool {
+	id := c.GetID(label + "_drag")
+	editLabel := label + "_drag_edit"
+	editID := c.GetID(editLabel + "_input")
+
+	if c.dragEditID == id {
+		return c.dragNumberEdit(id, editLabel, editID, value, min, max, decimals, x, y, w, h)
+	}
+
+	c.registerFocusable(id)
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, x, y, w, h)
+	focused := c.isFocused(id)
+
+	if hovered {
+		c.setHot(id)
+	}
+
+	old := *value
+	text := strconv.FormatFloat(*value, 'f', decimals, 64)
+	moved := false
+	startEdit := focused && c.input.IsKeyJustPressed(input.KeyEnter)
+	if hovered && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+		c.setFocused(id)
+		focused = true
+		if c.ctrlHeld {
+			startEdit = true
+		} else {
+			c.setActive(id)
+			c.dragStartX, c.dragStartValue = mx, *value
+		}
+	}
+
+	if startEdit {
+		c.dragEditID = id
+		c.dragEditText = text
+		c.dragEditState = TextInputState{CursorPos: len(text)}
+		c.setFocused(editID) // the text input takes over next frame
+	}
+
+	if c.isActive(id) {
+		if c.input.IsButtonPressed(input.MouseButtonLeft) {
+			*value = c.dragStartValue + float64(mx-c.dragStartX)*speed
+			moved = true
+		} else {
+			c.clearActive()
+		}
+	}
+
+	if focused {
+		if c.input.IsKeyJustPressed(input.KeyLeft) {
+			*value -= step
+			moved = true
+		}
+		if c.input.IsKeyJustPressed(input.KeyRight) {
+			*value += step
+			moved = true
+		}
+	}
+
+	// Values the caller set are shown rounded but left as they are
+	if moved {
+		*value = clampDrag(roundTo(*value, decimals), min, max)
+		text = strconv.FormatFloat(*value, 'f', decimals, 64)
+	}
+
+	bgColor := c.color(ColorInput)
+	if hovered || c.isActive(id) {
+		bgColor = c.color(ColorInputFocused)
+	}
+	rounding := c.style.FrameRounding
+	if focused {
+		c.roundedRectOutline(x, y, w, h, rounding, 2, bgColor, c.color(ColorFocusRing))
+	} else {
+		c.roundedRect(x, y, w, h, rounding, bgColor)
+	}
+
+	fontSize := c.style.FontSize
+	c.pushClip(x+2, y+2, w-4, h-4)
+	c.text(text, x+(w-int(c.renderer.MeasureText(text, fontSize)))/2, y+(h-int(fontSize))/2, fontSize, c.color(ColorText))
+	c.popClip()
+
+	return *value != old
+}
+
+// dragNumberEdit draws the text input of a drag field being typed into and applies the
+// typed value when it is submitted or loses focus.
+func (c *Context) dragNumberEdit(id ID, editLabel string, editID ID, value *float64, min, max float64, decimals int, x, y, w, h int) bool {
+	_, submitted := c.InputText(editLabel, &c.dragEditText, &c.dragEditState, x, y, w, h)
+
+	switch {
+	case c.input.IsKeyJustPressed(input.KeyEscape):
+		c.escapeUsed = true
+		c.dragEditID = 0
+		c.setFocused(id)
+		return false
+	case submitted:
+		c.dragEditID = 0
+		c.setFocused(id)
+	case !c.isFocused(editID):
+		c.dragEditID = 0
+	default:
+		return false
+	}
+
+	typed, err := strconv.ParseFloat(strings.TrimSpace(c.dragEditText), 64)
+	if err != nil {
+		return false
+	}
+	old := *value
+	*value = clampDrag(roundTo(typed, decimals), min, max)
+	return *value != old
+}
+
+// roundTo rounds v to the given number of decimals.
+func roundTo(v float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Round(v*scale) / scale
+}
+
+// clampDrag keeps v within [min, max], or leaves it as is when min and max are equal.
+func clampDrag(v, min, max float64) float64 {
+	if min == max {
+		return v
+	}
+	return math.Min(math.Max(v, min), max)
+}
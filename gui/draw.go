@@ -0,0 +1,135 @@
+package gui
+
+import (
+	"image"
+	"image/color"
+	"sort"
+
+	"github.com/dfirebaugh/hlg"
+)
+
+// drawLayer holds drawing deferred to the end of the frame. Widgets draw straight into
+// hlg's batch in call order, so panels, menus and modal popups, which must cover widgets
+// drawn after them, draw into a layer instead. Layers are drawn at End above everything
+// drawn directly: the layers of panels in the panels' z-order, then the other layers in
+// the order they were opened.
+type drawLayer struct {
+	cmds      []func()
+	clipRects [][4]int // the clip rects open when the layer was opened, restored at endLayer
+	panelID   ID       // the current panel when the layer was opened
+	panel     ID       // the panel drawn in the layer, or 0
+}
+
+// beginLayer makes drawing go to a new layer until the matching endLayer. The layer is
+// not clipped by the scroll regions open around it.
+func (c *Context) beginLayer() {
+	l := &drawLayer{clipRects: c.clipRects, panelID: c.currentPanelID}
+	c.clipRects = nil
+	c.layers = append(c.layers, l)
+	c.layerStack = append(c.layerStack, l)
+}
+
+// endLayer makes drawing go back to where it went before the last beginLayer.
+func (c *Context) endLayer() {
+	if len(c.layerStack) == 0 {
+		return
+	}
+	for len(c.clipRects) > 0 {
+		c.popClip() // clips left open in the layer
+	}
+	l := c.layerStack[len(c.layerStack)-1]
+	c.layerStack = c.layerStack[:len(c.layerStack)-1]
+	c.clipRects = l.clipRects
+	c.currentPanelID = l.panelID
+}
+
+// drawLayers draws the layers recorded this frame and clears them.
+func (c *Context) drawLayers() {
+	rank := func(l *drawLayer) int {
+		if l.panel == 0 {
+			return len(c.panelOrder)
+		}
+		return c.panelZ(l.panel)
+	}
+	sort.SliceStable(c.layers, func(i, j int) bool { return rank(c.layers[i]) < rank(c.layers[j]) })
+	for _, l := range c.layers {
+		for _, cmd := range l.cmds {
+			cmd()
+		}
+	}
+	c.layers = c.layers[:0]
+}
+
+// deferred returns the layer drawing currently goes to, or nil when it goes to hlg.
+func (c *Context) deferred() *drawLayer {
+	if len(c.layerStack) == 0 {
+		return nil
+	}
+	return c.layerStack[len(c.layerStack)-1]
+}
+
+func (c *Context) filledRect(x, y, w, h int, col color.Color) {
+	if l := c.deferred(); l != nil {
+		l.cmds = append(l.cmds, func() { c.renderer.FilledRect(x, y, w, h, col) })
+		return
+	}
+	c.renderer.FilledRect(x, y, w, h, col)
+}
+
+func (c *Context) filledTriangle(x1, y1, x2, y2, x3, y3 int, col color.Color) {
+	if l := c.deferred(); l != nil {
+		l.cmds = append(l.cmds, func() { c.renderer.FilledTriangle(x1, y1, x2, y2, x3, y3, col) })
+		return
+	}
+	c.renderer.FilledTriangle(x1, y1, x2, y2, x3, y3, col)
+}
+
+func (c *Context) roundedRect(x, y, w, h, radius int, col color.Color) {
+	if l := c.deferred(); l != nil {
+		l.cmds = append(l.cmds, func() { c.renderer.RoundedRect(x, y, w, h, radius, col) })
+		return
+	}
+	c.renderer.RoundedRect(x, y, w, h, radius, col)
+}
+
+func (c *Context) roundedRectOutline(x, y, w, h, radius, outlineW int, fill, outline color.Color) {
+	if l := c.deferred(); l != nil {
+		l.cmds = append(l.cmds, func() { c.renderer.RoundedRectOutline(x, y, w, h, radius, outlineW, fill, outline) })
+		return
+	}
+	c.renderer.RoundedRectOutline(x, y, w, h, radius, outlineW, fill, outline)
+}
+
+func (c *Context) text(s string, x, y int, fontSize float32, col color.Color) {
+	if l := c.deferred(); l != nil {
+		l.cmds = append(l.cmds, func() { c.renderer.Text(s, x, y, fontSize, col) })
+		return
+	}
+	c.renderer.Text(s, x, y, fontSize, col)
+}
+
+func (c *Context) pushClipRect(x, y, w, h int) {
+	if l := c.deferred(); l != nil {
+		l.cmds = append(l.cmds, func() { c.renderer.PushClipRect(x, y, w, h) })
+		return
+	}
+	c.renderer.PushClipRect(x, y, w, h)
+}
+
+func (c *Context) popClipRect() {
+	if l := c.deferred(); l != nil {
+		l.cmds = append(l.cmds, c.renderer.PopClipRect)
+		return
+	}
+	c.renderer.PopClipRect()
+}
+
+// image draws the src rectangle of a texture, in texture pixels, stretched over the given
+// rectangle.
+func (c *Context) image(tex *hlg.Texture, src image.Rectangle, x, y, w, h int) {
+	if l := c.deferred(); l != nil {
+		l.cmds = append(l.cmds, func() { c.renderer.Image(tex, src, x, y, w, h) })
+		return
+	}
+	c.renderer.Image(tex, src, x, y, w, h)
+}
@@ -0,0 +1,51 @@
+// Package guitest runs gui widgets without a window, so their behavior can be tested.
+//
+// A Harness pairs a gui.Context with a FakeInput, which scripts the input of each
+// frame, and a Recorder, which records the draw calls of each frame:
+//
+//	func TestSaveButton(t *testing.T) {
+//	    h := guitest.New(800, 600)
+//	    clicked := false
+//	    draw := func(ctx *gui.Context) {
+//	        if ctx.Button("Save", 10, 10, 100, 30) {
+//	            clicked = true
+//	        }
+//	    }
+//	    h.Frame(draw)
+//	    h.Input.Click(20, 20)
+//	    h.Frame(draw)
+//	    if !clicked {
+//	        t.Fatal("Save was not clicked")
+//	    }
+//	    if _, ok := h.Draw.FindText("Save"); !ok {
+//	        t.Fatal("Save label not drawn")
+//	    }
+//	}
+package guitest
+
+import "github.com/dfirebaugh/hlg/gui"
+
+// Harness runs the frames of a gui.Context on scripted input.
+type Harness struct {
+	Ctx   *gui.Context
+	Input *FakeInput
+	Draw  *Recorder
+}
+
+// New creates a Harness with a screen of the given size.
+func New(width, height int) *Harness {
+	in := NewFakeInput()
+	rec := NewRecorder(width, height)
+	ctx := gui.NewContext(in)
+	ctx.SetRenderer(rec)
+	return &Harness{Ctx: ctx, Input: in, Draw: rec}
+}
+
+// Frame applies the input scripted since the last frame and runs one frame of the
+// Context, calling draw between Begin and End.
+func (h *Harness) Frame(draw func(ctx *gui.Context)) {
+	h.Input.Update()
+	h.Ctx.Begin()
+	draw(h.Ctx)
+	h.Ctx.End()
+}
@@ -0,0 +1,345 @@
+package guitest_test
+
+import (
+	"testing"
+
+	"github.com/dfirebaugh/hlg/gui"
+	"github.com/dfirebaugh/hlg/gui/guitest"
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// focusRings returns the outlines drawn in the last frame in the focus ring color.
+func focusRings(h *guitest.Harness) []guitest.Command {
+	ring := h.Ctx.GetStyle().Colors[gui.ColorFocusRing]
+	var rings []guitest.Command
+	for _, cmd := range h.Draw.Commands() {
+		if cmd.Kind == guitest.RoundedRectOutline && cmd.Outline == ring {
+			rings = append(rings, cmd)
+		}
+	}
+	return rings
+}
+
+// caretX returns the x of the text caret drawn in the last frame, or -1 if none was drawn.
+func caretX(h *guitest.Harness) int {
+	caret := h.Ctx.GetStyle().Colors[gui.ColorCaret]
+	for _, cmd := range h.Draw.Commands() {
+		if cmd.Kind == guitest.FilledRect && cmd.Color == caret {
+			return cmd.X
+		}
+	}
+	return -1
+}
+
+func TestTabFocusOrder(t *testing.T) {
+	h := guitest.New(800, 600)
+	labels := []string{"One", "Two", "Three"}
+	clicked := ""
+	draw := func(ctx *gui.Context) {
+		for i, label := range labels {
+			if ctx.Button(label, 10, 10+40*i, 100, 30) {
+				clicked = label
+			}
+		}
+	}
+	// focused returns the label of the button drawn with a focus ring
+	focused := func() string {
+		rings := focusRings(h)
+		if len(rings) != 1 {
+			t.Fatalf("%d focus rings drawn, want 1", len(rings))
+		}
+		return labels[(rings[0].Y-11)/40]
+	}
+
+	h.Frame(draw)
+	if rings := focusRings(h); len(rings) != 0 {
+		t.Fatalf("focus ring drawn before any focus: %v", rings)
+	}
+
+	for _, want := range []string{"One", "Two", "Three", "One"} {
+		h.Input.TapKey(input.KeyTab)
+		h.Frame(draw) // focus moves once every widget has registered
+		h.Frame(draw)
+		if got := focused(); got != want {
+			t.Fatalf("tab focused %s, want %s", got, want)
+		}
+	}
+
+	h.Input.PressKey(input.KeyLeftShift)
+	for _, want := range []string{"Three", "Two"} {
+		h.Input.TapKey(input.KeyTab)
+		h.Frame(draw)
+		h.Frame(draw)
+		if got := focused(); got != want {
+			t.Fatalf("shift+tab focused %s, want %s", got, want)
+		}
+	}
+	h.Input.ReleaseKey(input.KeyLeftShift)
+
+	h.Input.TapKey(input.KeyEnter)
+	h.Frame(draw)
+	if clicked != "Two" {
+		t.Fatalf("enter clicked %q, want the focused Two", clicked)
+	}
+}
+
+func TestButtonClick(t *testing.T) {
+	h := guitest.New(800, 600)
+	clicks := 0
+	draw := func(ctx *gui.Context) {
+		if ctx.Button("Save", 10, 10, 100, 30) {
+			clicks++
+		}
+	}
+	h.Frame(draw)
+	if cmd, ok := h.Draw.FindText("Save"); !ok || cmd.X != 10+(100-cmd.W)/2 {
+		t.Fatalf("label not drawn centered: %+v", cmd)
+	}
+
+	h.Input.Click(20, 20)
+	h.Frame(draw)
+	h.Frame(draw)
+	if clicks != 1 {
+		t.Fatalf("clicked %d times, want 1", clicks)
+	}
+
+	// Releasing outside the button cancels the click
+	h.Input.MoveMouse(20, 20)
+	h.Input.PressButton(input.MouseButtonLeft)
+	h.Frame(draw)
+	h.Input.MoveMouse(300, 300)
+	h.Input.ReleaseButton(input.MouseButtonLeft)
+	h.Frame(draw)
+	if clicks != 1 {
+		t.Fatalf("release outside clicked, %d clicks", clicks)
+	}
+
+	// Clicking a miss leaves no focus
+	h.Input.Click(300, 300)
+	h.Frame(draw)
+	h.Input.TapKey(input.KeyEnter)
+	h.Frame(draw)
+	if clicks != 1 {
+		t.Fatalf("enter clicked an unfocused button, %d clicks", clicks)
+	}
+
+	// A held key that repeats clicks once
+	h.Input.Click(20, 20)
+	h.Frame(draw)
+	h.Input.PressKey(input.KeyEnter)
+	h.Frame(draw)
+	h.Input.RepeatKey(input.KeyEnter)
+	h.Frame(draw)
+	h.Input.ReleaseKey(input.KeyEnter)
+	h.Frame(draw)
+	if clicks != 3 {
+		t.Fatalf("clicked %d times, want 3: the mouse click and one for the held enter", clicks)
+	}
+}
+
+func TestSliderDrag(t *testing.T) {
+	h := guitest.New(800, 600)
+	value := float32(0)
+	changed := false
+	draw := func(ctx *gui.Context) {
+		changed = ctx.Slider("volume", &value, 0, 100, 10, 10, 200, 10)
+	}
+	h.Frame(draw)
+
+	steps := []struct {
+		x       int
+		want    float32
+		changed bool
+	}{
+		{60, 25, true},   // pressing jumps to the mouse
+		{160, 75, true},  // dragging follows it
+		{160, 75, false}, // holding still changes nothing
+		{400, 100, true}, // past the end stops at max
+		{-50, 0, true},   // and before the start at min
+	}
+	h.Input.MoveMouse(60, 15)
+	h.Input.PressButton(input.MouseButtonLeft)
+	for _, step := range steps {
+		h.Input.MoveMouse(step.x, 15)
+		h.Frame(draw)
+		if value != step.want || changed != step.changed {
+			t.Fatalf("at x %d: value %v changed %v, want %v changed %v", step.x, value, changed, step.want, step.changed)
+		}
+	}
+
+	h.Input.ReleaseButton(input.MouseButtonLeft)
+	h.Frame(draw)
+	h.Input.MoveMouse(110, 15)
+	h.Frame(draw)
+	if value != 0 || changed {
+		t.Fatalf("moving after release set %v", value)
+	}
+
+	// The focused slider steps by a twentieth of its range
+	h.Input.TapKey(input.KeyRight)
+	h.Frame(draw)
+	if value != 5 || !changed {
+		t.Fatalf("right key set %v, want 5", value)
+	}
+}
+
+func TestDragFloat(t *testing.T) {
+	h := guitest.New(800, 600)
+	value := float32(5)
+	draw := func(ctx *gui.Context) {
+		ctx.DragFloat("speed", &value, 0.5, 0, 10, 10, 10, 100, 24)
+	}
+	h.Frame(draw)
+	if _, ok := h.Draw.FindText("5.000"); !ok {
+		t.Fatalf("value not drawn, texts %q", h.Draw.Texts())
+	}
+
+	h.Input.MoveMouse(50, 20)
+	h.Input.PressButton(input.MouseButtonLeft)
+	h.Frame(draw)
+	for _, step := range []struct {
+		x    int
+		want float32
+	}{
+		{56, 8},   // half a unit per pixel from where the drag began
+		{100, 10}, // kept within max
+		{30, 0},   // and min
+		{48, 4},
+	} {
+		h.Input.MoveMouse(step.x, 20)
+		h.Frame(draw)
+		if value != step.want {
+			t.Fatalf("dragged to x %d: value %v, want %v", step.x, value, step.want)
+		}
+	}
+	h.Input.ReleaseButton(input.MouseButtonLeft)
+	h.Frame(draw)
+	if _, ok := h.Draw.FindText("4.000"); !ok {
+		t.Fatalf("dragged value not drawn, texts %q", h.Draw.Texts())
+	}
+
+	h.Input.TapKey(input.KeyLeft)
+	h.Frame(draw)
+	if value != 3.5 {
+		t.Fatalf("left key set %v, want 3.5", value)
+	}
+}
+
+func TestInputTextEditing(t *testing.T) {
+	h := guitest.New(800, 600)
+	text := ""
+	var state gui.TextInputState
+	draw := func(ctx *gui.Context) {
+		ctx.InputText("name", &text, &state, 10, 10, 200, 30)
+	}
+	charW := int(guitest.CharWidth * h.Ctx.GetStyle().FontSize)
+
+	h.Input.Click(20, 20)
+	h.Frame(draw)
+
+	steps := []struct {
+		name   string
+		script func(in *guitest.FakeInput)
+		text   string
+		cursor int
+	}{
+		{"type", func(in *guitest.FakeInput) { in.Type("hello") }, "hello", 5},
+		{"left", func(in *guitest.FakeInput) { in.TapKey(input.KeyLeft) }, "hello", 4},
+		{"backspace", func(in *guitest.FakeInput) { in.TapKey(input.KeyBackspace) }, "helo", 3},
+		{"insert", func(in *guitest.FakeInput) { in.Type("L") }, "helLo", 4},
+		{"home", func(in *guitest.FakeInput) { in.TapKey(input.KeyHome) }, "helLo", 0},
+		{"backspace at start", func(in *guitest.FakeInput) { in.TapKey(input.KeyBackspace) }, "helLo", 0},
+		{"delete", func(in *guitest.FakeInput) { in.TapKey(input.KeyDelete) }, "elLo", 0},
+		{"right", func(in *guitest.FakeInput) { in.TapKey(input.KeyRight) }, "elLo", 1},
+		{"end", func(in *guitest.FakeInput) { in.TapKey(input.KeyEnd) }, "elLo", 4},
+		{"right at end", func(in *guitest.FakeInput) { in.TapKey(input.KeyRight) }, "elLo", 4},
+		{"hold backspace", func(in *guitest.FakeInput) { in.PressKey(input.KeyBackspace) }, "elL", 3},
+		{"backspace repeats", func(in *guitest.FakeInput) { in.RepeatKey(input.KeyBackspace) }, "el", 2},
+		{"release backspace", func(in *guitest.FakeInput) { in.ReleaseKey(input.KeyBackspace) }, "el", 2},
+		{"repeat of a released key", func(in *guitest.FakeInput) { in.RepeatKey(input.KeyBackspace) }, "el", 2},
+	}
+	for _, step := range steps {
+		step.script(h.Input)
+		h.Frame(draw)
+		if text != step.text || state.CursorPos != step.cursor {
+			t.Fatalf("%s: text %q cursor %d, want %q cursor %d", step.name, text, state.CursorPos, step.text, step.cursor)
+		}
+		if got, want := caretX(h), 10+4+step.cursor*charW; got != want {
+			t.Fatalf("%s: caret drawn at x %d, want %d", step.name, got, want)
+		}
+	}
+	if _, ok := h.Draw.FindText("el"); !ok {
+		t.Fatalf("text not drawn, texts %q", h.Draw.Texts())
+	}
+
+	// Clicking between characters puts the caret there
+	h.Input.Click(10+4+charW+1, 20)
+	h.Frame(draw)
+	if state.CursorPos != 1 {
+		t.Fatalf("click put the cursor at %d, want 1", state.CursorPos)
+	}
+
+	// Clicking elsewhere ends editing
+	h.Input.Click(400, 400)
+	h.Frame(draw)
+	h.Input.Type("x")
+	h.Frame(draw)
+	if text != "el" || caretX(h) != -1 {
+		t.Fatalf("unfocused field took typing: %q", text)
+	}
+}
+
+func TestInputTextRuneBoundaries(t *testing.T) {
+	h := guitest.New(800, 600)
+	text := "héllo"
+	state := gui.TextInputState{CursorPos: 2} // within the two bytes of é
+	draw := func(ctx *gui.Context) {
+		ctx.InputText("name", &text, &state, 10, 10, 200, 30)
+	}
+	charW := int(guitest.CharWidth * h.Ctx.GetStyle().FontSize)
+
+	// Clicking to the right of é puts the caret after it
+	h.Input.Click(10+4+2*charW+1, 20)
+	h.Frame(draw)
+	if state.CursorPos != 3 {
+		t.Fatalf("click put the cursor at %d, want 3", state.CursorPos)
+	}
+
+	steps := []struct {
+		name   string
+		script func(in *guitest.FakeInput)
+		text   string
+		cursor int
+	}{
+		{"left over é", func(in *guitest.FakeInput) { in.TapKey(input.KeyLeft) }, "héllo", 1},
+		{"right over é", func(in *guitest.FakeInput) { in.TapKey(input.KeyRight) }, "héllo", 3},
+		{"backspace é", func(in *guitest.FakeInput) { in.TapKey(input.KeyBackspace) }, "hllo", 1},
+		{"type ü", func(in *guitest.FakeInput) { in.Type("ü") }, "hüllo", 3},
+	}
+	for _, step := range steps {
+		step.script(h.Input)
+		h.Frame(draw)
+		if text != step.text || state.CursorPos != step.cursor {
+			t.Fatalf("%s: text %q cursor %d, want %q cursor %d", step.name, text, state.CursorPos, step.text, step.cursor)
+		}
+	}
+
+	// A caret the caller left within a character is drawn before it
+	state.CursorPos = 2
+	state.ClearSelection()
+	h.Frame(draw)
+	if state.CursorPos != 1 {
+		t.Fatalf("cursor within ü kept at %d, want 1", state.CursorPos)
+	}
+	if got, want := caretX(h), 10+4+charW; got != want {
+		t.Fatalf("caret drawn at x %d, want %d", got, want)
+	}
+
+	// A caret past the end of text the caller shortened is drawn at its end
+	text = "hü"
+	state.CursorPos = 10
+	h.Frame(draw)
+	if state.CursorPos != len(text) || caretX(h) != 10+4+2*charW {
+		t.Fatalf("cursor past the end kept at %d, caret at x %d", state.CursorPos, caretX(h))
+	}
+}
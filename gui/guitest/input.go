@@ -0,0 +1,212 @@
+package guitest
+
+import (
+	"github.com/dfirebaugh/hlg/gui"
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// FakeInput is a gui.InputContext driven by a script instead of a window. Moves,
+// presses, keys and typed text are queued until the next Update, which makes them the
+// input of the following frame, so a test scripts a frame's input and then runs the
+// frame:
+//
+//	in.MoveMouse(40, 20)
+//	in.PressButton(input.MouseButtonLeft)
+//	h.Frame(draw) // the button is just pressed
+//	in.ReleaseButton(input.MouseButtonLeft)
+//	h.Frame(draw) // the button is just released
+//
+// It also implements gui.Clipboard, keeping copied text in memory, and
+// gui.CursorSetter, recording the cursor shape.
+type FakeInput struct {
+	mouseX, mouseY int
+	buttons        map[input.MouseButton]bool
+	keys           map[input.Key]bool
+	pending        []func()
+
+	// Changes applied by the last Update
+	justPressed  map[input.MouseButton]bool
+	justReleased map[input.MouseButton]bool
+	keysPressed  map[input.Key]bool
+	keysRepeated map[input.Key]bool
+	runes        []rune
+	scrollX      float64
+	scrollY      float64
+
+	typed              []rune
+	pendingX, pendingY float64
+	clipboard          string
+	cursor             input.CursorShape
+}
+
+// NewFakeInput creates a FakeInput with the mouse at (0, 0) and nothing pressed.
+func NewFakeInput() *FakeInput {
+	return &FakeInput{
+		buttons:      make(map[input.MouseButton]bool),
+		keys:         make(map[input.Key]bool),
+		justPressed:  make(map[input.MouseButton]bool),
+		justReleased: make(map[input.MouseButton]bool),
+		keysPressed:  make(map[input.Key]bool),
+		keysRepeated: make(map[input.Key]bool),
+	}
+}
+
+// MoveMouse moves the mouse to (x, y).
+func (f *FakeInput) MoveMouse(x, y int) {
+	f.pending = append(f.pending, func() { f.mouseX, f.mouseY = x, y })
+}
+
+// PressButton presses a mouse button and holds it until ReleaseButton.
+func (f *FakeInput) PressButton(button input.MouseButton) {
+	f.pending = append(f.pending, func() {
+		if !f.buttons[button] {
+			f.buttons[button] = true
+			f.justPressed[button] = true
+		}
+	})
+}
+
+// ReleaseButton releases a mouse button.
+func (f *FakeInput) ReleaseButton(button input.MouseButton) {
+	f.pending = append(f.pending, func() {
+		if f.buttons[button] {
+			f.buttons[button] = false
+			f.justReleased[button] = true
+		}
+	})
+}
+
+// Click moves the mouse to (x, y) and presses and releases the left button, both within
+// the next frame. Widgets that act on the release, such as buttons, see a click; use
+// PressButton and ReleaseButton in separate frames to hold the button down.
+func (f *FakeInput) Click(x, y int) {
+	f.MoveMouse(x, y)
+	f.PressButton(input.MouseButtonLeft)
+	f.ReleaseButton(input.MouseButtonLeft)
+}
+
+// PressKey presses a key and holds it until ReleaseKey, as for modifiers.
+func (f *FakeInput) PressKey(key input.Key) {
+	f.pending = append(f.pending, func() {
+		if !f.keys[key] {
+			f.keys[key] = true
+			f.keysPressed[key] = true
+		}
+	})
+}
+
+// RepeatKey sends a key repeat of a held key, as the platform does while a key is held.
+func (f *FakeInput) RepeatKey(key input.Key) {
+	f.pending = append(f.pending, func() {
+		if f.keys[key] {
+			f.keysRepeated[key] = true
+		}
+	})
+}
+
+// ReleaseKey releases a key.
+func (f *FakeInput) ReleaseKey(key input.Key) {
+	f.pending = append(f.pending, func() { f.keys[key] = false })
+}
+
+// TapKey presses and releases a key within the next frame.
+func (f *FakeInput) TapKey(key input.Key) {
+	f.PressKey(key)
+	f.ReleaseKey(key)
+}
+
+// Type queues text as typed characters of the next frame.
+func (f *FakeInput) Type(text string) {
+	f.typed = append(f.typed, []rune(text)...)
+}
+
+// Scroll queues mouse wheel movement for the next frame. Positive y scrolls up.
+func (f *FakeInput) Scroll(x, y float64) {
+	f.pendingX += x
+	f.pendingY += y
+}
+
+// MousePosition returns the mouse position.
+func (f *FakeInput) MousePosition() (int, int) {
+	return f.mouseX, f.mouseY
+}
+
+// IsButtonPressed returns true if the button is held down.
+func (f *FakeInput) IsButtonPressed(button input.MouseButton) bool {
+	return f.buttons[button]
+}
+
+// IsButtonJustPressed returns true if the button was pressed this frame.
+func (f *FakeInput) IsButtonJustPressed(button input.MouseButton) bool {
+	return f.justPressed[button]
+}
+
+// IsButtonJustReleased returns true if the button was released this frame.
+func (f *FakeInput) IsButtonJustReleased(button input.MouseButton) bool {
+	return f.justReleased[button]
+}
+
+// IsKeyPressed returns true if the key is held down.
+func (f *FakeInput) IsKeyPressed(key input.Key) bool {
+	return f.keys[key]
+}
+
+// IsKeyJustPressed returns true if the key was pressed this frame.
+func (f *FakeInput) IsKeyJustPressed(key input.Key) bool {
+	return f.keysPressed[key]
+}
+
+// IsKeyRepeated returns true if the key repeated this frame.
+func (f *FakeInput) IsKeyRepeated(key input.Key) bool {
+	return f.keysRepeated[key]
+}
+
+// GetCharInput returns the runes typed this frame.
+func (f *FakeInput) GetCharInput() []rune {
+	return f.runes
+}
+
+// ScrollDelta returns the mouse wheel movement this frame.
+func (f *FakeInput) ScrollDelta() (x, y float64) {
+	return f.scrollX, f.scrollY
+}
+
+// Update starts the next frame, applying the input queued since the last Update.
+func (f *FakeInput) Update() {
+	clear(f.justPressed)
+	clear(f.justReleased)
+	clear(f.keysPressed)
+	clear(f.keysRepeated)
+	for _, apply := range f.pending {
+		apply()
+	}
+	f.pending = f.pending[:0]
+
+	f.runes, f.typed = f.typed, nil
+	f.scrollX, f.scrollY = f.pendingX, f.pendingY
+	f.pendingX, f.pendingY = 0, 0
+}
+
+// SetCursorShape records the cursor shape widgets show.
+func (f *FakeInput) SetCursorShape(shape input.CursorShape) {
+	f.cursor = shape
+}
+
+// CursorShape returns the cursor shape widgets last showed.
+func (f *FakeInput) CursorShape() input.CursorShape {
+	return f.cursor
+}
+
+// GetClipboardText returns the text last copied.
+func (f *FakeInput) GetClipboardText() string {
+	return f.clipboard
+}
+
+// SetClipboardText sets the copied text.
+func (f *FakeInput) SetClipboardText(text string) {
+	f.clipboard = text
+}
+
+var _ gui.InputContext = (*FakeInput)(nil)
+var _ gui.Clipboard = (*FakeInput)(nil)
+var _ gui.CursorSetter = (*FakeInput)(nil)
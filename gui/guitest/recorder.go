@@ -0,0 +1,191 @@
+package guitest
+
+import (
+	"image"
+	"image/color"
+	"unicode/utf8"
+
+	"github.com/dfirebaugh/hlg"
+	"github.com/dfirebaugh/hlg/gui"
+)
+
+// CharWidth is the width of every character, per pixel of font size, in text measured
+// by a Recorder. A fixed width keeps layouts the same on every machine.
+const CharWidth = 0.5
+
+// CommandKind identifies what a recorded draw command draws.
+type CommandKind int
+
+const (
+	FilledRect CommandKind = iota
+	FilledTriangle
+	RoundedRect
+	RoundedRectOutline
+	Text
+	Image
+	PushClipRect
+	PopClipRect
+)
+
+var commandKindNames = [...]string{
+	FilledRect:         "FilledRect",
+	FilledTriangle:     "FilledTriangle",
+	RoundedRect:        "RoundedRect",
+	RoundedRectOutline: "RoundedRectOutline",
+	Text:               "Text",
+	Image:              "Image",
+	PushClipRect:       "PushClipRect",
+	PopClipRect:        "PopClipRect",
+}
+
+// String returns the name of the Renderer method that records the command.
+func (k CommandKind) String() string {
+	if k < 0 || int(k) >= len(commandKindNames) {
+		return "CommandKind(?)"
+	}
+	return commandKindNames[k]
+}
+
+// Command is a draw call recorded by a Recorder. Only the fields of its kind are set.
+type Command struct {
+	Kind       CommandKind
+	X, Y, W, H int // the rectangle drawn; for Text, the position and measured size
+
+	Points [3]image.Point // corners of a FilledTriangle
+
+	Radius       int // corner radius of rounded rectangles
+	OutlineWidth int
+	Color        color.Color // fill color, or the color of text
+	Outline      color.Color
+
+	Text     string
+	FontSize float32
+
+	Texture *hlg.Texture
+	Src     image.Rectangle
+}
+
+// Recorder is a gui.Renderer that records draw calls instead of drawing, for testing
+// widgets without a window. Text is measured with every character CharWidth times the
+// font size wide and the font size high.
+type Recorder struct {
+	width, height int
+	frame         []Command // commands of the frame being drawn
+	last          []Command // commands of the last frame drawn
+}
+
+// NewRecorder creates a Recorder with a screen of the given size.
+func NewRecorder(width, height int) *Recorder {
+	return &Recorder{width: width, height: height}
+}
+
+// SetScreenSize changes the size of the screen widgets are laid out on.
+func (r *Recorder) SetScreenSize(width, height int) {
+	r.width, r.height = width, height
+}
+
+// Commands returns the draw calls of the last frame, between BeginDraw and EndDraw, in
+// the order they were drawn.
+func (r *Recorder) Commands() []Command {
+	return r.last
+}
+
+// Texts returns the strings drawn in the last frame, in the order they were drawn.
+func (r *Recorder) Texts() []string {
+	var texts []string
+	for _, cmd := range r.last {
+		if cmd.Kind == Text {
+			texts = append(texts, cmd.Text)
+		}
+	}
+	return texts
+}
+
+// FindText returns the first command of the last frame that drew s.
+func (r *Recorder) FindText(s string) (Command, bool) {
+	for _, cmd := range r.last {
+		if cmd.Kind == Text && cmd.Text == s {
+			return cmd, true
+		}
+	}
+	return Command{}, false
+}
+
+func (r *Recorder) record(cmd Command) {
+	r.frame = append(r.frame, cmd)
+}
+
+func (r *Recorder) BeginDraw() {
+	r.frame = nil
+}
+
+func (r *Recorder) EndDraw() {
+	r.last = r.frame
+	r.frame = nil
+}
+
+func (r *Recorder) ScreenSize() (w, h int) {
+	return r.width, r.height
+}
+
+func (r *Recorder) FilledRect(x, y, w, h int, col color.Color) {
+	r.record(Command{Kind: FilledRect, X: x, Y: y, W: w, H: h, Color: col})
+}
+
+func (r *Recorder) FilledTriangle(x1, y1, x2, y2, x3, y3 int, col color.Color) {
+	r.record(Command{Kind: FilledTriangle, Points: [3]image.Point{{x1, y1}, {x2, y2}, {x3, y3}}, Color: col})
+}
+
+func (r *Recorder) RoundedRect(x, y, w, h, radius int, col color.Color) {
+	r.record(Command{Kind: RoundedRect, X: x, Y: y, W: w, H: h, Radius: radius, Color: col})
+}
+
+func (r *Recorder) RoundedRectOutline(x, y, w, h, radius, outlineW int, fill, outline color.Color) {
+	r.record(Command{
+		Kind: RoundedRectOutline, X: x, Y: y, W: w, H: h,
+		Radius: radius, OutlineWidth: outlineW, Color: fill, Outline: outline,
+	})
+}
+
+func (r *Recorder) Text(s string, x, y int, fontSize float32, col color.Color) {
+	r.record(Command{
+		Kind: Text, X: x, Y: y, W: int(r.MeasureText(s, fontSize)), H: int(fontSize),
+		Text: s, FontSize: fontSize, Color: col,
+	})
+}
+
+func (r *Recorder) Image(tex *hlg.Texture, src image.Rectangle, x, y, w, h int) {
+	r.record(Command{Kind: Image, X: x, Y: y, W: w, H: h, Texture: tex, Src: src})
+}
+
+func (r *Recorder) PushClipRect(x, y, w, h int) {
+	r.record(Command{Kind: PushClipRect, X: x, Y: y, W: w, H: h})
+}
+
+func (r *Recorder) PopClipRect() {
+	r.record(Command{Kind: PopClipRect})
+}
+
+// SetFont does nothing; every font is measured the same.
+func (r *Recorder) SetFont(font *hlg.Font) {}
+
+func (r *Recorder) MeasureText(s string, fontSize float32) float32 {
+	return float32(utf8.RuneCountInString(s)) * CharWidth * fontSize
+}
+
+func (r *Recorder) TextIndexAtPoint(s string, fontSize, x, y float32) int {
+	charW := CharWidth * fontSize
+	for i := range s {
+		if x < r.MeasureText(s[:i], fontSize)+charW/2 {
+			return i
+		}
+	}
+	return len(s)
+}
+
+func (r *Recorder) TextCaretRect(s string, fontSize float32, index int) (x, y, w, h float32) {
+	index = min(max(index, 0), len(s))
+	return r.MeasureText(s[:index], fontSize), 0, 1, fontSize
+}
+
+var _ gui.Renderer = (*Recorder)(nil)
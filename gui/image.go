@@ -0,0 +1,67 @@
+package gui
+
+import (
+	"image"
+
+	"github.com/dfirebaugh/hlg"
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// imageButtonPadding is the space between the edge of an image button and its image.
+const imageButtonPadding = 4
+
+// Image draws the src rectangle of a texture, in texture pixels, stretched over the
+// given rectangle. Pass the texture's whole bounds as src to draw all of it, or a frame
+// of a sprite sheet to draw just that frame.
+// The texture's clip, size and position are changed to draw it.
+func (c *Context) Image(tex *hlg.Texture, src image.Rectangle, x, y, w, h int) {
+	if tex == nil {
+		return
+	}
+	c.image(tex, src, x, y, w, h)
+}
+
+// ImageButton renders a button showing the src rectangle of a texture and returns true
+// if clicked. The label is used for widget identification (not displayed).
+func (c *Context) ImageButton(label string, tex *hlg.Texture, src image.Rectangle, x, y, w, h int) bool {
+	id := c.GetID(label + "_imagebutton")
+	c.registerFocusable(id)
+
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, x, y, w, h)
+	focused := c.isFocused(id)
+
+	if hovered {
+		c.setHot(id)
+	}
+
+	clicked := false
+	if hovered && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+		c.setActive(id)
+		c.setFocused(id)
+	}
+	if c.isActive(id) && c.input.IsButtonJustReleased(input.MouseButtonLeft) {
+		clicked = hovered
+		c.clearActive()
+	}
+	if focused && (c.input.IsKeyJustPressed(input.KeyEnter) || c.input.IsKeyJustPressed(input.KeySpace)) {
+		clicked = true
+	}
+
+	bgColor := c.color(ColorButton)
+	if c.isActive(id) {
+		bgColor = c.color(ColorButtonActive)
+	} else if hovered || focused {
+		bgColor = c.color(ColorButtonHovered)
+	}
+
+	rounding := c.style.FrameRounding
+	if focused {
+		c.roundedRectOutline(x, y, w, h, rounding, 2, bgColor, c.color(ColorFocusRing))
+	} else {
+		c.roundedRect(x, y, w, h, rounding, bgColor)
+	}
+
+	c.Image(tex, src, x+imageButtonPadding, y+imageButtonPadding, w-2*imageButtonPadding, h-2*imageButtonPadding)
+	return clicked
+}
@@ -1,10 +1,10 @@
 package gui
 
 import (
-	"image/color"
+	"strings"
 	"time"
+	"unicode"
 
-	"github.com/dfirebaugh/hlg"
 	"github.com/dfirebaugh/hlg/pkg/input"
 )
 
@@ -16,6 +16,9 @@ const (
 // The text is stored in the provided pointer.
 // The state parameter holds cursor and selection information.
 // The label is used for widget identification (not displayed).
+// Shift with the arrows, home and end selects; ctrl+a selects all, and ctrl+c, ctrl+x
+// and ctrl+v copy, cut and paste through the Context's Clipboard, pasting line breaks
+// as spaces.
 func (c *Context) InputText(label string, text *string, state *TextInputState, x, y, w, h int) (changed, submitted bool) {
 	id := c.GetID(label + "_input")
 	c.registerFocusable(id)
@@ -27,11 +30,15 @@ func (c *Context) InputText(label string, text *string, state *TextInputState, x
 
 	if hovered {
 		c.setHot(id)
+		c.setCursor(input.CursorIBeam)
 	}
 
+	// The caret and selection are drawn at byte offsets in the text
+	state.clamp(*text)
+
 	if hovered && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
 		c.setFocused(id)
-		state.CursorPos = c.calculateCursorPosition(*text, mx-x-4)
+		state.CursorPos = clampToRune(*text, c.calculateCursorPosition(*text, mx-x-4))
 		state.ClearSelection()
 	}
 
@@ -41,110 +48,146 @@ func (c *Context) InputText(label string, text *string, state *TextInputState, x
 
 	state.focused = focused
 
-	bgColor := color.RGBA{40, 40, 50, 255}
+	bgColor := c.color(ColorInput)
 	if focused {
-		bgColor = color.RGBA{50, 50, 60, 255}
+		bgColor = c.color(ColorInputFocused)
 	}
-	hlg.RoundedRect(x, y, w, h, 4, bgColor)
+	rounding := c.style.FrameRounding
+	c.roundedRect(x, y, w, h, rounding, bgColor)
 
 	if focused {
-		hlg.RoundedRectOutline(x+2, y+2, w-4, h-4, 2, 2, color.RGBA{100, 150, 255, 255}, color.RGBA{100, 150, 255, 255})
+		focusColor := c.color(ColorFocusRing)
+		c.roundedRectOutline(x+2, y+2, w-4, h-4, max(rounding-2, 0), 2, focusColor, focusColor)
 	}
 
-	hlg.PushClipRect(x+2, y+2, w-4, h-4)
+	c.pushClip(x+2, y+2, w-4, h-4)
+
+	fontSize := c.style.FontSize
+	textY := y + (h-int(fontSize))/2
+	if state.HasSelection() {
+		start := min(state.SelectionStart, state.SelectionEnd)
+		end := max(state.SelectionStart, state.SelectionEnd)
+		startX, _, _, _ := c.renderer.TextCaretRect(*text, fontSize, start)
+		endX, _, _, _ := c.renderer.TextCaretRect(*text, fontSize, end)
+		c.filledRect(x+4+int(min(startX, endX)), y+4, int(max(startX, endX)-min(startX, endX)), h-8, c.color(ColorSelection))
+	}
 
-	textColor := color.RGBA{220, 220, 220, 255}
-	textY := y + (h-14)/2
-	hlg.Text(*text, x+4, textY, 14, textColor)
+	c.text(*text, x+4, textY, fontSize, c.color(ColorText))
 
 	if focused {
 		elapsed := time.Since(c.frameTime)
 		showCursor := (elapsed/blinkInterval)%2 == 0
 
 		if showCursor || c.isActive(id) {
-			textBeforeCursor := (*text)[:state.CursorPos]
-			cursorX := x + 4 + int(hlg.MeasureText(textBeforeCursor, 14))
-			cursorColor := color.RGBA{200, 200, 200, 255}
-			hlg.FilledRect(cursorX, y+4, 2, h-8, cursorColor)
+			caretX, _, _, _ := c.renderer.TextCaretRect(*text, fontSize, state.CursorPos)
+			cursorX := x + 4 + int(caretX)
+			c.filledRect(cursorX, y+4, 2, h-8, c.color(ColorCaret))
 		}
 	}
 
-	hlg.PopClipRect()
+	c.popClip()
 
 	return changed, submitted
 }
 
 // calculateCursorPosition determines cursor position from x offset.
 func (c *Context) calculateCursorPosition(text string, xOffset int) int {
-	if xOffset <= 0 {
-		return 0
-	}
-
-	for i := 1; i <= len(text); i++ {
-		width := hlg.MeasureText(text[:i], 14)
-		if int(width) >= xOffset {
-			prevWidth := float32(0)
-			if i > 1 {
-				prevWidth = hlg.MeasureText(text[:i-1], 14)
-			}
-			if float32(xOffset)-prevWidth < width-float32(xOffset) {
-				return i - 1
-			}
-			return i
-		}
-	}
-	return len(text)
+	return c.renderer.TextIndexAtPoint(text, c.style.FontSize, float32(xOffset), 0)
 }
 
 // handleTextInput processes keyboard input for text editing.
 func (c *Context) handleTextInput(text *string, state *TextInputState) (changed, submitted bool) {
-	chars := c.input.GetCharInput()
-	for _, ch := range chars {
-		if ch >= 32 && ch < 127 { // Printable ASCII
-			*text = (*text)[:state.CursorPos] + string(ch) + (*text)[state.CursorPos:]
-			state.CursorPos++
-			changed = true
+	// The selection runs from its anchor to the caret
+	ed := textEditor{text: text, cursor: state.CursorPos, anchor: state.CursorPos}
+	if state.HasSelection() {
+		ed.anchor = state.SelectionStart
+		if ed.anchor == state.CursorPos {
+			ed.anchor = state.SelectionEnd
 		}
 	}
+	startText := *text
+	pressed, repeating := c.input.IsKeyJustPressed, c.keyRepeating
+	shift, ctrl := c.shiftHeld, c.ctrlHeld
 
-	if c.input.IsKeyJustPressed(input.KeyBackspace) {
-		if state.CursorPos > 0 {
-			*text = (*text)[:state.CursorPos-1] + (*text)[state.CursorPos:]
-			state.CursorPos--
-			changed = true
+	for _, ch := range c.input.GetCharInput() {
+		if unicode.IsPrint(ch) {
+			ed.replaceSelection(string(ch))
 		}
 	}
 
-	if c.input.IsKeyJustPressed(input.KeyDelete) {
-		if state.CursorPos < len(*text) {
-			*text = (*text)[:state.CursorPos] + (*text)[state.CursorPos+1:]
-			changed = true
+	if repeating(input.KeyBackspace) {
+		if !ed.hasSelection() && ed.cursor > 0 {
+			ed.anchor = prevRune(*text, ed.cursor)
 		}
+		ed.replaceSelection("")
 	}
 
-	if c.input.IsKeyJustPressed(input.KeyLeft) {
-		if state.CursorPos > 0 {
-			state.CursorPos--
+	if repeating(input.KeyDelete) {
+		if !ed.hasSelection() && ed.cursor < len(*text) {
+			ed.anchor = nextRune(*text, ed.cursor)
+		}
+		ed.replaceSelection("")
+	}
+
+	if ctrl {
+		switch {
+		case pressed(input.KeyA):
+			ed.anchor, ed.cursor = 0, len(*text)
+		case pressed(input.KeyC) && ed.hasSelection():
+			c.clipboard.SetClipboardText(ed.selectedText())
+		case pressed(input.KeyX) && ed.hasSelection():
+			c.clipboard.SetClipboardText(ed.selectedText())
+			ed.replaceSelection("")
+		case pressed(input.KeyV):
+			if paste := singleLineReplacer.Replace(c.clipboard.GetClipboardText()); paste != "" {
+				ed.replaceSelection(paste)
+			}
+		}
+	}
+
+	if repeating(input.KeyLeft) {
+		start, _ := ed.selection()
+		switch {
+		case ed.hasSelection() && !shift:
+			ed.moveTo(start, false)
+		case ed.cursor > 0:
+			ed.moveTo(prevRune(*text, ed.cursor), shift)
 		}
 	}
 
-	if c.input.IsKeyJustPressed(input.KeyRight) {
-		if state.CursorPos < len(*text) {
-			state.CursorPos++
+	if repeating(input.KeyRight) {
+		_, end := ed.selection()
+		switch {
+		case ed.hasSelection() && !shift:
+			ed.moveTo(end, false)
+		case ed.cursor < len(*text):
+			ed.moveTo(nextRune(*text, ed.cursor), shift)
 		}
 	}
 
-	if c.input.IsKeyJustPressed(input.KeyHome) {
-		state.CursorPos = 0
+	if pressed(input.KeyHome) {
+		ed.moveTo(0, shift)
 	}
 
-	if c.input.IsKeyJustPressed(input.KeyEnd) {
-		state.CursorPos = len(*text)
+	if pressed(input.KeyEnd) {
+		ed.moveTo(len(*text), shift)
 	}
 
-	if c.input.IsKeyJustPressed(input.KeyEnter) {
+	if pressed(input.KeyEnter) {
 		submitted = true
 	}
 
-	return changed, submitted
+	state.CursorPos = ed.cursor
+	state.SelectionStart, state.SelectionEnd = ed.anchor, ed.cursor
+	return *text != startText, submitted
+}
+
+// keyRepeating returns true if the key was pressed or repeated this frame, for the
+// editing keys that keep going while held.
+func (c *Context) keyRepeating(key input.Key) bool {
+	return c.input.IsKeyJustPressed(key) || c.input.IsKeyRepeated(key)
 }
+
+// singleLineReplacer turns the line breaks of text pasted into a single line field into
+// spaces.
+var singleLineReplacer = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
@@ -0,0 +1,538 @@
+package gui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/dfirebaugh/hlg"
+)
+
+// Size describes how a widget or container is sized along one axis of a layout.
+type Size struct {
+	kind  sizeKind
+	value float32
+}
+
+type sizeKind int
+
+const (
+	sizeAuto sizeKind = iota
+	sizeFixed
+	sizePercent
+	sizeFill
+)
+
+// Auto sizes to the natural size of the widget, such as the width of a button's label.
+func Auto() Size {
+	return Size{kind: sizeAuto}
+}
+
+// Fixed sizes to px pixels.
+func Fixed(px int) Size {
+	return Size{kind: sizeFixed, value: float32(px)}
+}
+
+// Percent sizes to a percentage (0-100) of the content size of the containing layout.
+func Percent(p float32) Size {
+	return Size{kind: sizePercent, value: p}
+}
+
+// Fill takes the space left in the containing layout. In a row with several Fill cells
+// the space is shared equally between them.
+func Fill() Size {
+	return Size{kind: sizeFill}
+}
+
+type layoutDirection int
+
+const (
+	directionColumn layoutDirection = iota
+	directionRow
+)
+
+// layout places widgets inside a container, line by line.
+// Columns put every widget on its own line unless SameLine is called; rows put widgets
+// side by side and start a new line when the row's cell widths are used up or, without
+// cell widths, when the next widget does not fit.
+type layout struct {
+	direction  layoutDirection
+	x, y, w, h int // content rect; h is 0 when the height is unbounded
+	spacing    int
+	widths     []Size // row cell widths, repeated on every line of the row
+	autoWidth  bool   // the container reports its content width instead of its slot width
+	indent     int    // space left of the content, for the children of tree nodes
+	panel      *PanelState
+	scroll     *scrollRegion
+	tableCell  bool // a table cell, closed by the next cell, row or EndTable
+	root       bool
+
+	cell      int // index in widths of the next cell
+	cursorX   int
+	cursorY   int
+	lineH     int // height of the tallest item on the current line
+	lineItems int
+	lineNatW  int // natural width of the items on the current line
+	sameLine  bool
+
+	usedW, usedH int // natural extent of the content, used for auto sizing
+}
+
+func (l *layout) newLine() {
+	l.cursorX = l.x
+	l.cursorY += l.lineH + l.spacing
+	l.lineH = 0
+	l.lineItems = 0
+	l.lineNatW = 0
+	l.cell = 0
+}
+
+// slot returns the position and width of the next item without reserving it.
+// Rows with cell widths use the next cell's width instead of w.
+func (l *layout) slot(w Size, natW int) (x, y, width int) {
+	if l.lineItems > 0 {
+		switch {
+		case l.direction == directionColumn && !l.sameLine:
+			l.newLine()
+		case l.direction == directionRow && len(l.widths) > 0 && l.cell >= len(l.widths):
+			l.newLine()
+		}
+	}
+	l.sameLine = false
+
+	if l.direction == directionRow && len(l.widths) > 0 {
+		w = l.widths[l.cell]
+	}
+	width = l.resolveWidth(w, natW)
+	if l.direction == directionRow && len(l.widths) == 0 && l.lineItems > 0 && l.cursorX+width > l.x+l.w {
+		l.newLine()
+		width = l.resolveWidth(w, natW)
+	}
+	if l.direction == directionRow && len(l.widths) > 0 {
+		l.cell++
+	}
+	return l.cursorX, l.cursorY, width
+}
+
+// commit reserves an item of the given size at the position returned by slot.
+// natW is the width the item would like, which is what auto sizing measures.
+func (l *layout) commit(width, height, natW int) {
+	if l.lineItems > 0 {
+		l.lineNatW += l.spacing
+	}
+	l.lineNatW += natW
+	l.lineItems++
+	l.cursorX += width + l.spacing
+	l.lineH = max(l.lineH, height)
+	l.usedW = max(l.usedW, l.lineNatW)
+	l.usedH = max(l.usedH, l.cursorY-l.y+height)
+}
+
+func (l *layout) resolveWidth(s Size, natural int) int {
+	remaining := max(l.x+l.w-l.cursorX, 0)
+	switch s.kind {
+	case sizeFixed:
+		return int(s.value)
+	case sizePercent:
+		return int(s.value / 100 * float32(l.w))
+	case sizeFill:
+		if l.direction != directionRow || len(l.widths) == 0 {
+			return remaining
+		}
+		// Leave room for the fixed cells after this one and share the rest between the fill cells
+		fills := 0
+		for _, cw := range l.widths[l.cell:] {
+			switch cw.kind {
+			case sizeFill:
+				fills++
+			case sizeFixed, sizePercent:
+				remaining -= l.resolveWidth(cw, 0)
+			}
+		}
+		remaining -= (len(l.widths) - l.cell - 1) * l.spacing
+		return max(remaining/max(fills, 1), 0)
+	}
+	return natural
+}
+
+func (l *layout) resolveHeight(s Size, natural int) int {
+	switch s.kind {
+	case sizeFixed:
+		return int(s.value)
+	case sizePercent:
+		if l.h > 0 {
+			return int(s.value / 100 * float32(l.h))
+		}
+	case sizeFill:
+		if l.h > 0 {
+			return max(l.y+l.h-l.cursorY, 0)
+		}
+	}
+	return natural
+}
+
+// naturalWidth returns the width an item sized by s asks for, for auto sizing.
+// Fill and percent items report their natural width so they do not keep an auto sized
+// container at whatever width it had.
+func naturalWidth(s Size, width, natural int) int {
+	if s.kind == sizeFixed {
+		return width
+	}
+	return natural
+}
+
+// currentLayout returns the innermost layout, creating a screen-sized one if none is open.
+func (c *Context) currentLayout() *layout {
+	if len(c.layouts) == 0 {
+		sw, sh := c.renderer.ScreenSize()
+		c.pushLayout(&layout{direction: directionColumn, root: true}, 0, 0, sw, sh, c.style.Padding)
+	}
+	return c.layouts[len(c.layouts)-1]
+}
+
+// pushLayout opens l on the given rect, shrunk by padding on every side.
+func (c *Context) pushLayout(l *layout, x, y, w, h, padding int) {
+	l.x, l.y = x+padding, y+padding
+	l.w = max(w-2*padding, 0)
+	if h > 0 {
+		l.h = max(h-2*padding, 0)
+	}
+	l.cursorX, l.cursorY = l.x, l.y
+	l.spacing = c.style.Spacing
+	if len(c.layouts) > 0 {
+		l.spacing = c.layouts[len(c.layouts)-1].spacing
+	}
+	c.layouts = append(c.layouts, l)
+}
+
+// nextRect places an item with the given natural size in the current layout.
+// A width set with SetNextWidth overrides w.
+func (c *Context) nextRect(w, h Size, natW, natH int) (x, y, width, height int) {
+	if c.nextWidth != nil {
+		w = *c.nextWidth
+		c.nextWidth = nil
+	}
+	l := c.currentLayout()
+	x, y, width = l.slot(w, natW)
+	height = l.resolveHeight(h, natH)
+	l.commit(width, height, naturalWidth(w, width, natW))
+	return x, y, width, height
+}
+
+// NextRect reserves the next rectangle of the current layout and returns it.
+// Use it to place widgets that take absolute coordinates, or custom drawing, in a layout.
+// An Auto width fills the rest of the line and an Auto height is one line of widgets.
+func (c *Context) NextRect(w, h Size) (x, y, width, height int) {
+	if w.kind == sizeAuto {
+		w = Fill()
+	}
+	return c.nextRect(w, h, 0, c.style.ItemHeight)
+}
+
+// SetNextWidth overrides the width of the next widget added to the layout.
+func (c *Context) SetNextWidth(w Size) {
+	c.nextWidth = &w
+}
+
+// SameLine places the next widget of a column to the right of the previous one.
+func (c *Context) SameLine() {
+	c.currentLayout().sameLine = true
+}
+
+// Spacing inserts px pixels of empty space in the current layout.
+func (c *Context) Spacing(px int) {
+	l := c.currentLayout()
+	l.slot(Fixed(px), px)
+	l.commit(px, px, px)
+}
+
+// SetSpacing sets the space between widgets in the current layout and in the layouts
+// opened inside it.
+func (c *Context) SetSpacing(px int) {
+	c.currentLayout().spacing = px
+}
+
+// SetPadding sets the space between the edge of panels and layouts opened from now on
+// and their content. It changes the Padding of the current style.
+func (c *Context) SetPadding(px int) {
+	c.style.Padding = px
+}
+
+// BeginLayout opens a column layout on an arbitrary rectangle of the screen.
+// Pass 0 for h to leave the height unbounded. Close it with EndLayout.
+func (c *Context) BeginLayout(x, y, w, h int) {
+	c.currentLayout()
+	c.pushLayout(&layout{direction: directionColumn, root: true}, x, y, w, h, c.style.Padding)
+}
+
+// EndLayout closes the layout opened by BeginLayout, along with any rows and columns
+// left open inside it.
+func (c *Context) EndLayout() {
+	for len(c.layouts) > 1 {
+		l := c.layouts[len(c.layouts)-1]
+		if l.panel != nil || l.scroll != nil || l.tableCell {
+			return
+		}
+		c.layouts = c.layouts[:len(c.layouts)-1]
+		if l.root {
+			return
+		}
+	}
+}
+
+// BeginRow opens a row in the next slot of the current layout. Widgets added to the
+// row are placed side by side. With widths, each widget takes the next cell width and
+// a new line starts when the widths are used up, which suits forms:
+//
+//	ctx.BeginRow(gui.Percent(30), gui.Fill())
+//	ctx.AddLabel("Name")
+//	ctx.AddInputText("name", &name, &nameState)
+//	ctx.EndRow()
+//
+// Fill cells share the space left by the fixed and percent cells and by the cells placed
+// before them. Without widths, widgets take their natural width and wrap when the row is full.
+func (c *Context) BeginRow(widths ...Size) {
+	c.beginContainer(&layout{direction: directionRow, widths: widths}, Fill())
+}
+
+// EndRow closes the row opened by BeginRow.
+func (c *Context) EndRow() {
+	c.endContainer()
+}
+
+// BeginColumn opens a column of the given width in the next slot of the current layout.
+// Widgets added to the column are stacked top to bottom. An Auto column is laid out
+// in the rest of the line and takes the width of its content.
+func (c *Context) BeginColumn(width Size) {
+	c.beginContainer(&layout{direction: directionColumn}, width)
+}
+
+// EndColumn closes the column opened by BeginColumn.
+func (c *Context) EndColumn() {
+	c.endContainer()
+}
+
+func (c *Context) beginContainer(l *layout, width Size) {
+	if c.nextWidth != nil {
+		width = *c.nextWidth
+		c.nextWidth = nil
+	}
+	parent := c.currentLayout()
+	if width.kind == sizeAuto {
+		// Lay the content out in the rest of the line and report its width at the end,
+		// unless the parent row hands out a cell width
+		l.autoWidth = parent.direction != directionRow || len(parent.widths) == 0
+		width = Fill()
+	}
+	x, y, w := parent.slot(width, 0)
+	c.pushLayout(l, x+l.indent, y, w-l.indent, 0, 0)
+}
+
+func (c *Context) endContainer() {
+	if len(c.layouts) < 2 {
+		return
+	}
+	l := c.layouts[len(c.layouts)-1]
+	if l.root || l.panel != nil || l.scroll != nil || l.tableCell {
+		return
+	}
+	c.layouts = c.layouts[:len(c.layouts)-1]
+
+	width := l.w
+	if l.autoWidth {
+		width = l.usedW
+	}
+	c.layouts[len(c.layouts)-1].commit(width+l.indent, l.usedH, l.usedW+l.indent)
+}
+
+// beginPanelLayout opens the column that flows the widgets of a panel's body.
+func (c *Context) beginPanelLayout(state *PanelState, x, y, w, h int) {
+	c.currentLayout()
+	c.pushLayout(&layout{direction: directionColumn, panel: state}, x, y, w, h, c.style.Padding)
+}
+
+// EndPanel closes the body of the panel opened by Panel, along with any rows and
+// columns left open inside it, and records the size of its content for auto sizing.
+// Panels are also closed by the next Panel call and by End.
+func (c *Context) EndPanel() {
+	for i := len(c.layouts) - 1; i >= 0; i-- {
+		l := c.layouts[i]
+		if l.root || l.scroll != nil || l.tableCell {
+			return
+		}
+		if l.panel != nil {
+			l.panel.contentW, l.panel.contentH = l.usedW, l.usedH
+			c.layouts = c.layouts[:i]
+			c.ClearCurrentPanel()
+			if d := c.deferred(); d != nil && d.panel != 0 {
+				c.endLayer()
+			}
+			return
+		}
+	}
+}
+
+// AddLabel adds a line of static text to the current layout.
+func (c *Context) AddLabel(text string) {
+	x, y, _, h := c.nextRect(Auto(), Auto(), int(c.renderer.MeasureText(text, c.style.FontSize)), c.style.ItemHeight)
+	c.Label(text, x, y+(h-int(c.style.FontSize))/2)
+}
+
+// AddButton adds a button sized to its label to the current layout and returns true if clicked.
+func (c *Context) AddButton(label string) bool {
+	x, y, w, h := c.nextRect(Auto(), Auto(), int(c.renderer.MeasureText(label, c.style.FontSize))+24, c.style.ItemHeight)
+	return c.Button(label, x, y, w, h)
+}
+
+// AddCheckbox adds a checkbox with its label to the current layout and returns true if
+// the state changed.
+func (c *Context) AddCheckbox(label string, checked *bool) bool {
+	const size = 18
+	x, y, _, h := c.nextRect(Auto(), Auto(), size+8+int(c.renderer.MeasureText(label, c.style.FontSize)), c.style.ItemHeight)
+	return c.Checkbox(label, checked, x, y+(h-size)/2, size)
+}
+
+// AddToggle adds a toggle switch to the current layout and returns true if the state changed.
+func (c *Context) AddToggle(label string, on *bool) bool {
+	const toggleH = 22
+	x, y, w, h := c.nextRect(Auto(), Auto(), 44, c.style.ItemHeight)
+	return c.Toggle(label, on, x, y+(h-toggleH)/2, w, toggleH)
+}
+
+// AddSlider adds a slider that fills the rest of the line to the current layout and
+// returns true if the value changed.
+func (c *Context) AddSlider(label string, value *float32, min, max float32) bool {
+	const trackH = 12
+	x, y, w, h := c.nextRect(Fill(), Auto(), 120, c.style.ItemHeight)
+	// The handle is trackH+4 tall and drawn from the top of the slider
+	return c.Slider(label, value, min, max, x, y+(h-trackH-4)/2, w, trackH)
+}
+
+// AddInputText adds a text input that fills the rest of the line to the current layout
+// and returns (changed, submitted).
+func (c *Context) AddInputText(label string, text *string, state *TextInputState) (changed, submitted bool) {
+	x, y, w, h := c.nextRect(Fill(), Auto(), 120, c.style.ItemHeight)
+	return c.InputText(label, text, state, x, y, w, h)
+}
+
+// AddCombo adds a combo box that fills the rest of the line to the current layout and
+// returns true if the selection changed.
+func (c *Context) AddCombo(label string, selected *int, items []string) bool {
+	natW := 0
+	for _, item := range items {
+		natW = max(natW, int(c.renderer.MeasureText(item, c.style.FontSize)))
+	}
+	x, y, w, h := c.nextRect(Fill(), Auto(), natW+40, c.style.ItemHeight)
+	return c.Combo(label, selected, items, x, y, w, h)
+}
+
+// AddListBox adds a list box that fills the rest of the line and shows rows items at
+// once to the current layout, and returns true if the selection changed.
+func (c *Context) AddListBox(label string, items []string, state *ListBoxState, rows int) bool {
+	x, y, w, h := c.nextRect(Fill(), Auto(), 120, rows*c.rowHeight()+4)
+	return c.ListBox(label, items, state, x, y, w, h)
+}
+
+// AddRadioGroup adds a radio button for each option to the current layout and returns
+// true if the selection changed.
+func (c *Context) AddRadioGroup(label string, selected *int, options []string) bool {
+	natW := 0
+	for _, option := range options {
+		natW = max(natW, radioSize+8+int(c.renderer.MeasureText(option, c.style.FontSize)))
+	}
+	x, y, _, _ := c.nextRect(Auto(), Auto(), natW, len(options)*c.style.ItemHeight)
+	return c.RadioGroup(label, selected, options, x, y)
+}
+
+// AddTextArea adds a text area that fills the rest of the line and shows lines lines of
+// text at once to the current layout, and returns true if the text changed.
+func (c *Context) AddTextArea(label string, text *string, state *TextAreaState, lines int) bool {
+	x, y, w, h := c.nextRect(Fill(), Auto(), 120, lines*c.textLineHeight()+4+2*textAreaPadding)
+	return c.TextArea(label, text, state, x, y, w, h)
+}
+
+// AddProgressBar adds a progress bar that fills the rest of the line to the current layout.
+func (c *Context) AddProgressBar(fraction float32, overlay string) {
+	x, y, w, h := c.nextRect(Fill(), Auto(), 120, c.style.ItemHeight)
+	c.ProgressBar(fraction, overlay, x, y, w, h)
+}
+
+// AddDragFloat adds a drag field that fills the rest of the line to the current layout
+// and returns true if the value changed.
+func (c *Context) AddDragFloat(label string, value *float32, speed, min, max float32) bool {
+	x, y, w, h := c.nextRect(Fill(), Auto(), 80, c.style.ItemHeight)
+	return c.DragFloat(label, value, speed, min, max, x, y, w, h)
+}
+
+// AddDragInt adds an integer drag field that fills the rest of the line to the current
+// layout and returns true if the value changed.
+func (c *Context) AddDragInt(label string, value *int, speed float32, min, max int) bool {
+	x, y, w, h := c.nextRect(Fill(), Auto(), 80, c.style.ItemHeight)
+	return c.DragInt(label, value, speed, min, max, x, y, w, h)
+}
+
+// AddColorEdit adds a color picker that fills the rest of the line to the current layout
+// and returns true if the color changed.
+func (c *Context) AddColorEdit(label string, col *color.RGBA) bool {
+	x, y, w, h := c.nextRect(Fill(), Auto(), 200, 200)
+	return c.ColorEdit(label, col, x, y, w, h)
+}
+
+// AddImage adds the src rectangle of a texture, drawn at the given size, to the current layout.
+func (c *Context) AddImage(tex *hlg.Texture, src image.Rectangle, w, h int) {
+	x, y, w, h := c.nextRect(Auto(), Auto(), w, h)
+	c.Image(tex, src, x, y, w, h)
+}
+
+// AddImageButton adds a button showing the src rectangle of a texture at the given
+// size to the current layout and returns true if clicked.
+func (c *Context) AddImageButton(label string, tex *hlg.Texture, src image.Rectangle, w, h int) bool {
+	x, y, w, h := c.nextRect(Auto(), Auto(), w+2*imageButtonPadding, h+2*imageButtonPadding)
+	return c.ImageButton(label, tex, src, x, y, w, h)
+}
+
+// AddTreeNode adds the header of a collapsible tree node that fills the rest of the line
+// to the current layout and returns true if the node is open. When it returns true, the
+// widgets added until the matching TreePop are indented below it:
+//
+//	if ctx.AddTreeNode("Player", &playerOpen) {
+//	    if ctx.AddSelectable("Sprite", selected == "sprite") {
+//	        selected = "sprite"
+//	    }
+//	    ctx.TreePop()
+//	}
+func (c *Context) AddTreeNode(label string, open *bool) bool {
+	natW := 18 + int(c.renderer.MeasureText(label, c.style.FontSize)) + 8
+	x, y, w, h := c.nextRect(Fill(), Auto(), natW, c.rowHeight())
+	c.TreeNode(label, open, x, y, w, h)
+	if *open {
+		c.beginContainer(&layout{direction: directionColumn, indent: c.style.IndentSpacing}, Fill())
+	}
+	return *open
+}
+
+// TreePop closes the children of the tree node opened by AddTreeNode.
+func (c *Context) TreePop() {
+	c.endContainer()
+}
+
+// AddSelectable adds a selectable row of text that fills the rest of the line to the
+// current layout and returns true if it was clicked.
+func (c *Context) AddSelectable(label string, selected bool) bool {
+	natW := int(c.renderer.MeasureText(label, c.style.FontSize)) + 12
+	x, y, w, h := c.nextRect(Fill(), Auto(), natW, c.rowHeight())
+	return c.Selectable(label, selected, x, y, w, h)
+}
+
+// AddTabBar adds a tab bar that fills the rest of the line to the current layout.
+// Add its tabs with TabItem and close it with EndTabBar; see TabBar.
+func (c *Context) AddTabBar(label string, selected *int) {
+	x, y, w, _ := c.nextRect(Fill(), Auto(), 0, c.style.ItemHeight)
+	c.TabBar(label, selected, x, y, w)
+}
+
+// AddMenuBar adds a menu bar that fills the rest of the line to the current layout.
+// Add its menus with Menu and close it with EndMenuBar; see BeginMenuBar.
+func (c *Context) AddMenuBar() {
+	x, y, w, _ := c.nextRect(Fill(), Auto(), 0, c.style.ItemHeight)
+	c.BeginMenuBar(x, y, w)
+}
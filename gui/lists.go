@@ -0,0 +1,331 @@
+package gui
+
+import "github.com/dfirebaugh/hlg/pkg/input"
+
+// radioSize is the diameter of a radio button.
+const radioSize = 18
+
+// rowHeight returns the height of an item in list boxes and combo popups.
+func (c *Context) rowHeight() int {
+	return int(c.style.FontSize) + 8
+}
+
+// Combo renders a drop-down showing items[*selected] that opens a list of the items when
+// clicked. The list is drawn above every other widget and closes when an item is picked
+// or the mouse is pressed elsewhere. While the combo has focus, enter or space open the
+// list and the up and down keys change the selection.
+// Returns true if the selection changed.
+func (c *Context) Combo(label string, selected *int, items []string, x, y, w, h int) bool {
+	id := c.GetID(label + "_combo")
+	c.registerFocusable(id)
+
+	mx, my := c.input.MousePosition()
+	blocked := c.isInputBlocked(mx, my)
+	hovered := !blocked && pointInRect(mx, my, x, y, w, h)
+	focused := c.isFocused(id)
+	open := c.popupID == id
+
+	changed := false
+	if c.pickedID == id {
+		changed = *selected != c.pickedItem
+		*selected = c.pickedItem
+		c.pickedID = 0
+	}
+
+	if hovered {
+		c.setHot(id)
+	}
+
+	if hovered && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+		c.setFocused(id)
+		focused = true
+		if open {
+			c.popupID = 0
+		} else {
+			c.openCombo(id, *selected, len(items))
+		}
+		open = !open
+	}
+
+	// Close the popup when focus moves away, e.g. with tab
+	if open && !focused {
+		c.popupID = 0
+		open = false
+	}
+
+	rowH := c.rowHeight()
+	popupH := min(len(items), maxComboRows)*rowH + 4
+	if focused && len(items) > 0 {
+		switch {
+		case open:
+			if c.input.IsKeyJustPressed(input.KeyUp) {
+				c.popupCursor = max(c.popupCursor-1, 0)
+				scrollIntoView(&c.popupScroll.ScrollY, c.popupCursor*rowH, rowH, popupH-4)
+			}
+			if c.input.IsKeyJustPressed(input.KeyDown) {
+				c.popupCursor = min(c.popupCursor+1, len(items)-1)
+				scrollIntoView(&c.popupScroll.ScrollY, c.popupCursor*rowH, rowH, popupH-4)
+			}
+			if c.input.IsKeyJustPressed(input.KeyEnter) || c.input.IsKeyJustPressed(input.KeySpace) {
+				changed = changed || *selected != c.popupCursor
+				*selected = c.popupCursor
+				c.popupID = 0
+				open = false
+			}
+			if c.input.IsKeyJustPressed(input.KeyEscape) {
+				c.escapeUsed = true
+				c.popupID = 0
+				open = false
+			}
+		case c.input.IsKeyJustPressed(input.KeyEnter) || c.input.IsKeyJustPressed(input.KeySpace):
+			c.openCombo(id, *selected, len(items))
+			open = true
+		case c.input.IsKeyJustPressed(input.KeyUp) && *selected > 0:
+			*selected--
+			changed = true
+		case c.input.IsKeyJustPressed(input.KeyDown) && *selected < len(items)-1:
+			*selected++
+			changed = true
+		}
+	}
+
+	bgColor := c.color(ColorButton)
+	if hovered || focused || open {
+		bgColor = c.color(ColorButtonHovered)
+	}
+
+	rounding := c.style.FrameRounding
+	c.roundedRect(x, y, w, h, rounding, bgColor)
+
+	if focused {
+		focusColor := c.color(ColorFocusRing)
+		c.roundedRectOutline(x+1, y+1, w-2, h-2, max(rounding-1, 0), 1, focusColor, focusColor)
+	}
+
+	fontSize := c.style.FontSize
+	textColor := c.color(ColorText)
+	if *selected >= 0 && *selected < len(items) {
+		c.text(items[*selected], x+8, y+(h-int(fontSize))/2+2, fontSize, textColor)
+	}
+	arrowX, arrowY := x+w-18, y+(h-6)/2
+	c.filledTriangle(arrowX, arrowY, arrowX+8, arrowY, arrowX+4, arrowY+6, textColor)
+
+	if open {
+		c.popupSeen = true
+		popupY := y + h
+		if _, sh := c.renderer.ScreenSize(); popupY+popupH > sh && y-popupH >= 0 {
+			popupY = y - popupH
+		}
+		sel := *selected
+		c.deferOverlay(func() {
+			c.comboPopup(label, id, items, sel, x, popupY, w, popupH, [4]int{x, y, w, h})
+		})
+	}
+
+	return changed
+}
+
+// ListBox renders a scrolling list of items and returns true if the selection changed.
+// Clicking an item selects it; in a multi-select list box ctrl-click toggles an item and
+// shift-click selects the range from the last clicked item. While the list box has focus,
+// the arrow, page up/down and home/end keys move the selection, with shift extending it,
+// and space toggles the item under the cursor of a multi-select list box.
+// Only the items in view are drawn, so the list can be long.
+func (c *Context) ListBox(label string, items []string, state *ListBoxState, x, y, w, h int) bool {
+	id := c.GetID(label + "_listbox")
+	c.registerFocusable(id)
+	focused := c.isFocused(id)
+
+	changed := false
+	rowH := c.rowHeight()
+	state.Cursor = min(max(state.Cursor, 0), max(len(items)-1, 0))
+
+	if focused && len(items) > 0 {
+		pageRows := max((h-4)/rowH, 1)
+		cursor := state.Cursor
+		switch {
+		case c.input.IsKeyJustPressed(input.KeyUp):
+			cursor--
+		case c.input.IsKeyJustPressed(input.KeyDown):
+			cursor++
+		case c.input.IsKeyJustPressed(input.KeyPageUp):
+			cursor -= pageRows
+		case c.input.IsKeyJustPressed(input.KeyPageDown):
+			cursor += pageRows
+		case c.input.IsKeyJustPressed(input.KeyHome):
+			cursor = 0
+		case c.input.IsKeyJustPressed(input.KeyEnd):
+			cursor = len(items) - 1
+		}
+		cursor = min(max(cursor, 0), len(items)-1)
+		if cursor != state.Cursor {
+			changed = selectListItem(state, cursor, c.shiftHeld, false)
+			scrollIntoView(&state.scroll.ScrollY, cursor*rowH, rowH, h-4)
+		}
+		if state.MultiSelect && c.input.IsKeyJustPressed(input.KeySpace) {
+			changed = selectListItem(state, state.Cursor, false, true)
+		}
+	}
+
+	rounding := c.style.FrameRounding
+	if focused {
+		c.roundedRectOutline(x, y, w, h, rounding, 2, c.color(ColorInput), c.color(ColorFocusRing))
+	} else {
+		c.roundedRect(x, y, w, h, rounding, c.color(ColorInput))
+	}
+
+	cursor := -1
+	if focused {
+		cursor = state.Cursor
+	}
+	pressed := c.listRows(label+"_rows", id, items, &state.scroll, x+2, y+2, w-4, h-4, state.IsSelected, cursor)
+	if pressed >= 0 {
+		c.setFocused(id)
+		changed = selectListItem(state, pressed, c.shiftHeld, c.ctrlHeld) || changed
+	}
+
+	return changed
+}
+
+// selectListItem moves the cursor of a list box to item i and updates the selection the
+// way a click with the given modifiers does. Returns true if the selection changed.
+func selectListItem(state *ListBoxState, i int, shift, ctrl bool) bool {
+	state.Cursor = i
+	if !state.MultiSelect {
+		changed := state.Selected != i
+		state.Selected = i
+		state.anchor = i
+		return changed
+	}
+
+	switch {
+	case ctrl:
+		state.SetSelected(i, !state.IsSelected(i))
+		state.anchor = i
+	case shift:
+		state.ClearSelection()
+		for j := min(state.anchor, i); j <= max(state.anchor, i); j++ {
+			state.SetSelected(j, true)
+		}
+	default:
+		state.ClearSelection()
+		state.SetSelected(i, true)
+		state.anchor = i
+	}
+	return true
+}
+
+// listRows draws the items of a list in a scroll region on the given rectangle and
+// returns the item pressed this frame, or -1. Only the rows in view are drawn.
+// Rows mark the widget id as hot while hovered; cursor is highlighted like a hovered row.
+func (c *Context) listRows(label string, id ID, items []string, scroll *ScrollState, x, y, w, h int, isSelected func(int) bool, cursor int) int {
+	rowH := c.rowHeight()
+
+	style := c.style
+	style.Padding = 0
+	c.PushStyle(style)
+	defer c.PopStyle()
+
+	c.BeginScroll(label, x, y, w, h, scroll)
+	rowsX, rowsY, rowsW, _ := c.NextRect(Fill(), Fixed(len(items)*rowH))
+
+	first := max(scroll.ScrollY/rowH, 0)
+	last := min((scroll.ScrollY+h)/rowH+1, len(items))
+
+	mx, my := c.input.MousePosition()
+	blocked := c.isInputBlocked(mx, my)
+	fontSize := c.style.FontSize
+	pressed := -1
+	for i := first; i < last; i++ {
+		rowY := rowsY + i*rowH
+		hovered := !blocked && pointInRect(mx, my, rowsX, rowY, rowsW, rowH)
+		if hovered {
+			c.setHot(id)
+			if c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+				pressed = i
+			}
+		}
+
+		switch {
+		case isSelected(i):
+			c.filledRect(rowsX, rowY, rowsW, rowH, c.color(ColorSelection))
+		case hovered || i == cursor:
+			c.filledRect(rowsX, rowY, rowsW, rowH, c.color(ColorItemHovered))
+		}
+		c.text(items[i], rowsX+6, rowY+(rowH-int(fontSize))/2, fontSize, c.color(ColorText))
+	}
+
+	c.EndScroll()
+	return pressed
+}
+
+// RadioGroup renders a radio button for each option, stacked top to bottom one item
+// height apart, and returns true if the selection changed. The group takes focus as a
+// whole; the arrow keys then move the selection.
+func (c *Context) RadioGroup(label string, selected *int, options []string, x, y int) bool {
+	id := c.GetID(label + "_radio")
+	c.registerFocusable(id)
+
+	mx, my := c.input.MousePosition()
+	blocked := c.isInputBlocked(mx, my)
+	focused := c.isFocused(id)
+	rowH := c.style.ItemHeight
+	fontSize := c.style.FontSize
+
+	changed := false
+	if focused && len(options) > 0 {
+		prev := c.input.IsKeyJustPressed(input.KeyUp) || c.input.IsKeyJustPressed(input.KeyLeft)
+		next := c.input.IsKeyJustPressed(input.KeyDown) || c.input.IsKeyJustPressed(input.KeyRight)
+		switch {
+		case prev && *selected > 0:
+			*selected--
+			changed = true
+		case next && *selected < len(options)-1:
+			*selected++
+			changed = true
+		}
+	}
+
+	for i, option := range options {
+		rowY := y + i*rowH
+		hitW := radioSize + 8 + int(c.renderer.MeasureText(option, fontSize))
+		hovered := !blocked && pointInRect(mx, my, x, rowY, hitW, rowH)
+
+		if hovered {
+			c.setHot(id)
+			if c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+				c.setActive(id)
+				c.setFocused(id)
+			}
+			if c.isActive(id) && c.input.IsButtonJustReleased(input.MouseButtonLeft) {
+				changed = changed || *selected != i
+				*selected = i
+			}
+		}
+
+		circleY := rowY + (rowH-radioSize)/2
+		circleColor := c.color(ColorCheckbox)
+		if hovered {
+			circleColor = c.color(ColorCheckboxHovered)
+		}
+		if focused && i == max(*selected, 0) {
+			c.roundedRectOutline(x, circleY, radioSize, radioSize, radioSize/2, 2, circleColor, c.color(ColorFocusRing))
+		} else {
+			c.roundedRect(x, circleY, radioSize, radioSize, radioSize/2, circleColor)
+		}
+
+		if i == *selected {
+			margin := radioSize/4 + 1
+			markSize := radioSize - margin*2
+			c.roundedRect(x+margin, circleY+margin, markSize, markSize, markSize/2, c.color(ColorCheckMark))
+		}
+
+		c.text(option, x+radioSize+8, rowY+(rowH-int(fontSize))/2, fontSize, c.color(ColorText))
+	}
+
+	if c.isActive(id) && c.input.IsButtonJustReleased(input.MouseButtonLeft) {
+		c.clearActive()
+	}
+
+	return changed
+}
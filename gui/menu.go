@@ -0,0 +1,319 @@
+package gui
+
+import "github.com/dfirebaugh/hlg/pkg/input"
+
+const (
+	minMenuWidth   = 120 // narrowest a menu is drawn
+	menuItemIndent = 12  // space between the sides of a menu and its item labels
+	shortcutGap    = 32  // least space between an item's label and its shortcut
+)
+
+// menuBar is a bar opened by BeginMenuBar.
+type menuBar struct {
+	x, y, w, h int
+	cursorX    int // where the next menu title goes
+}
+
+// menu is the open menu between Menu or BeginContextMenu and EndMenu. Its items are
+// drawn by EndMenu, once the width of the widest one is known.
+type menu struct {
+	id         ID
+	x, y       int
+	w          int // width of the widest item so far
+	itemY      int // where the next item goes
+	entries    []menuEntry
+	anchor     [4]int // clicks here do not close the menu, the opener handles them
+	justOpened bool
+
+	// State of the widgets around the menu, restored by EndMenu
+	clipRects [][4]int
+	panelID   ID
+}
+
+// menuEntry is an item or separator of a menu.
+type menuEntry struct {
+	label, shortcut string
+	highlighted     bool
+	separator       bool
+}
+
+// BeginMenuBar opens a bar of menus, one item high, across the given width. Add menus
+// to it with Menu and close it with EndMenuBar:
+//
+//	ctx.BeginMenuBar(0, 0, screenW)
+//	if ctx.Menu("File") {
+//	    if ctx.MenuItem("Save", "Ctrl+S") {
+//	        save()
+//	    }
+//	    ctx.MenuSeparator()
+//	    if ctx.MenuItem("Quit", "") {
+//	        quit()
+//	    }
+//	    ctx.EndMenu()
+//	}
+//	ctx.EndMenuBar()
+func (c *Context) BeginMenuBar(x, y, w int) {
+	h := c.style.ItemHeight
+	c.filledRect(x, y, w, h, c.color(ColorHeader))
+	c.menuBar = &menuBar{x: x, y: y, w: w, h: h, cursorX: x + 4}
+}
+
+// EndMenuBar closes the bar opened by BeginMenuBar.
+func (c *Context) EndMenuBar() {
+	c.menuBar = nil
+}
+
+// Menu adds a menu title to the bar opened by BeginMenuBar and returns true while its
+// menu is open. Clicking the title opens the menu below it; while a menu of the bar is
+// open, hovering another title opens that one instead. Add the items of an open menu
+// with MenuItem and close it with EndMenu.
+func (c *Context) Menu(label string) bool {
+	b := c.menuBar
+	if b == nil {
+		return false
+	}
+	id := c.GetID(label + "_menu")
+
+	fontSize := c.style.FontSize
+	x, w := b.cursorX, int(c.renderer.MeasureText(label, fontSize))+16
+	b.cursorX += w
+
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, x, b.y, w, b.h)
+	open := c.popupID == id
+
+	if hovered {
+		c.setHot(id)
+		switch {
+		case c.input.IsButtonJustPressed(input.MouseButtonLeft):
+			if open {
+				c.popupID = 0
+			} else {
+				c.openMenu(id, true)
+			}
+			open = !open
+		case !open && c.popupID != 0 && c.menuFromBar:
+			c.openMenu(id, true)
+			open = true
+		}
+	}
+
+	if hovered || open {
+		c.filledRect(x, b.y, w, b.h, c.color(ColorHeaderHovered))
+	}
+	c.text(label, x+8, b.y+(b.h-int(fontSize))/2, fontSize, c.color(ColorText))
+
+	if open {
+		c.beginMenu(id, x, b.y+b.h, [4]int{x, b.y, w, b.h})
+	}
+	return open
+}
+
+// BeginContextMenu opens a menu at the mouse when the given rectangle is right-clicked
+// and returns true while it is open. Add its items with MenuItem and close it with
+// EndContextMenu:
+//
+//	if ctx.BeginContextMenu("entity", x, y, w, h) {
+//	    if ctx.MenuItem("Delete", "Del") {
+//	        deleteEntity()
+//	    }
+//	    ctx.EndContextMenu()
+//	}
+func (c *Context) BeginContextMenu(label string, x, y, w, h int) bool {
+	id := c.GetID(label + "_context")
+
+	mx, my := c.input.MousePosition()
+	if !c.isInputBlocked(mx, my) && pointInRect(mx, my, x, y, w, h) && c.input.IsButtonJustPressed(input.MouseButtonRight) {
+		c.openMenu(id, false)
+		c.menuX, c.menuY = mx, my
+	}
+	if c.popupID != id {
+		return false
+	}
+
+	c.beginMenu(id, c.menuX, c.menuY, [4]int{c.menuX, c.menuY, 1, 1})
+	return true
+}
+
+// EndContextMenu closes the menu opened by BeginContextMenu.
+func (c *Context) EndContextMenu() {
+	c.EndMenu()
+}
+
+// openMenu opens the menu with the given id, closing any other menu or combo popup.
+func (c *Context) openMenu(id ID, fromBar bool) {
+	c.popupID = id
+	c.popupCursor = -1
+	c.menuFromBar = fromBar
+	c.menuOpened = true
+}
+
+// beginMenu starts filling the open menu at (x, y), moved to stay on screen.
+func (c *Context) beginMenu(id ID, x, y int, anchor [4]int) {
+	c.popupSeen = true
+
+	sw, sh := c.renderer.ScreenSize()
+	x = max(min(x, sw-c.menuW), 0)
+	if y+c.menuH > sh {
+		y = max(min(anchor[1], sh)-c.menuH, 0)
+	}
+
+	c.menu = &menu{
+		id:         id,
+		x:          x,
+		y:          y,
+		itemY:      y + 4,
+		anchor:     anchor,
+		justOpened: c.menuOpened,
+		clipRects:  c.clipRects,
+		panelID:    c.currentPanelID,
+	}
+	c.menuOpened = false
+
+	// The menu is above everything, so the scroll regions and panels around it do not clip it
+	c.clipRects = nil
+	c.SetCurrentPanel(id)
+}
+
+// MenuItem adds an item to the open menu and returns true if it was clicked, or chosen
+// with the arrow keys and enter. Choosing an item closes the menu. The shortcut, such as
+// "Ctrl+S", is only shown next to the label; pass "" for none.
+func (c *Context) MenuItem(label, shortcut string) bool {
+	m := c.menu
+	if m == nil {
+		return false
+	}
+	index := len(m.entries)
+	rowH := c.rowHeight()
+	y := m.itemY
+	m.itemY += rowH
+
+	fontSize := c.style.FontSize
+	w := 2*menuItemIndent + int(c.renderer.MeasureText(label, fontSize))
+	if shortcut != "" {
+		w += shortcutGap + int(c.renderer.MeasureText(shortcut, fontSize))
+	}
+	m.w = max(m.w, w)
+
+	// The menu's width is only known at EndMenu, so items are hit tested at last frame's
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, m.x, y, max(c.menuW, m.w), rowH)
+	if hovered {
+		c.setHot(m.id)
+	}
+
+	clicked := hovered && c.input.IsButtonJustReleased(input.MouseButtonLeft)
+	if index == c.popupCursor && (c.input.IsKeyJustPressed(input.KeyEnter) || c.input.IsKeyJustPressed(input.KeySpace)) {
+		clicked = true
+	}
+	if clicked {
+		c.popupID = 0
+	}
+
+	m.entries = append(m.entries, menuEntry{
+		label:       label,
+		shortcut:    shortcut,
+		highlighted: hovered || index == c.popupCursor,
+	})
+	return clicked
+}
+
+// MenuSeparator adds a line between groups of items to the open menu.
+func (c *Context) MenuSeparator() {
+	m := c.menu
+	if m == nil {
+		return
+	}
+	m.itemY += 9
+	m.entries = append(m.entries, menuEntry{separator: true})
+}
+
+// EndMenu closes the menu opened by Menu and draws it above every other widget. While a
+// menu is open, the up and down keys move between its items and escape closes it.
+func (c *Context) EndMenu() {
+	m := c.menu
+	if m == nil {
+		return
+	}
+	c.menu = nil
+	c.clipRects = m.clipRects
+	c.SetCurrentPanel(m.panelID)
+
+	w, h := max(m.w, minMenuWidth), m.itemY+4-m.y
+	c.menuW, c.menuH = w, h
+	c.registerPopupBounds(m.id, m.x, m.y, w, h)
+
+	if c.popupID == m.id {
+		c.menuKeys(m)
+	}
+
+	if !m.justOpened && c.popupID == m.id {
+		mx, my := c.input.MousePosition()
+		pressed := c.input.IsButtonJustPressed(input.MouseButtonLeft) || c.input.IsButtonJustPressed(input.MouseButtonRight)
+		a := m.anchor
+		if pressed && !pointInRect(mx, my, m.x, m.y, w, h) && !pointInRect(mx, my, a[0], a[1], a[2], a[3]) {
+			c.popupID = 0
+		}
+	}
+
+	c.deferOverlay(func() { c.drawMenu(m, w, h) })
+}
+
+// menuKeys moves the highlighted item of the open menu with the arrow keys, skipping
+// separators, and closes the menu on escape.
+func (c *Context) menuKeys(m *menu) {
+	if c.input.IsKeyJustPressed(input.KeyEscape) {
+		c.escapeUsed = true
+		c.popupID = 0
+		return
+	}
+	step := 0
+	if c.input.IsKeyJustPressed(input.KeyUp) {
+		step = -1
+	}
+	if c.input.IsKeyJustPressed(input.KeyDown) {
+		step = 1
+	}
+	if step == 0 || len(m.entries) == 0 {
+		return
+	}
+
+	cursor := c.popupCursor
+	if cursor < 0 && step < 0 {
+		cursor = len(m.entries)
+	}
+	for range m.entries {
+		cursor = (cursor + step + len(m.entries)) % len(m.entries)
+		if !m.entries[cursor].separator {
+			c.popupCursor = cursor
+			return
+		}
+	}
+}
+
+// drawMenu draws a menu's background and items at the given size.
+func (c *Context) drawMenu(m *menu, w, h int) {
+	c.roundedRectOutline(m.x, m.y, w, h, c.style.FrameRounding, 1, c.color(ColorPopup), c.color(ColorBorder))
+
+	rowH := c.rowHeight()
+	fontSize := c.style.FontSize
+	textColor := c.color(ColorText)
+	y := m.y + 4
+	for _, e := range m.entries {
+		if e.separator {
+			c.filledRect(m.x+menuItemIndent/2, y+4, w-menuItemIndent, 1, c.color(ColorBorder))
+			y += 9
+			continue
+		}
+		if e.highlighted {
+			c.filledRect(m.x+2, y, w-4, rowH, c.color(ColorItemHovered))
+		}
+		textY := y + (rowH-int(fontSize))/2
+		c.text(e.label, m.x+menuItemIndent, textY, fontSize, textColor)
+		if e.shortcut != "" {
+			shortcutW := int(c.renderer.MeasureText(e.shortcut, fontSize))
+			c.text(e.shortcut, m.x+w-menuItemIndent-shortcutW, textY, fontSize, textColor)
+		}
+		y += rowH
+	}
+}
@@ -0,0 +1,649 @@
+package gui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+const (
+	minPanelWidth     = 80 // narrowest a panel can be resized to
+	resizeGripSize    = 12 // side of the grip in the bottom right corner of floating panels
+	dockMargin        = 24 // distance from a screen edge within which a dropped panel docks
+	tabDetachDistance = 8  // distance a tab is dragged before it leaves its group
+)
+
+// DockSide is the screen edge a panel is docked into.
+type DockSide int
+
+const (
+	DockNone   DockSide = iota // the panel floats at its position
+	DockLeft                   // the panel fills the height of the left edge
+	DockRight                  // the panel fills the height of the right edge
+	DockTop                    // the panel fills the width of the top edge
+	DockBottom                 // the panel fills the width of the bottom edge
+)
+
+// dockSideNames are the names of the dock sides in panel layout JSON.
+var dockSideNames = [...]string{
+	DockNone:   "",
+	DockLeft:   "left",
+	DockRight:  "right",
+	DockTop:    "top",
+	DockBottom: "bottom",
+}
+
+// MarshalText returns the name of the side used in panel layout JSON.
+func (d DockSide) MarshalText() ([]byte, error) {
+	if d < 0 || int(d) >= len(dockSideNames) {
+		return nil, fmt.Errorf("invalid dock side %d", int(d))
+	}
+	return []byte(dockSideNames[d]), nil
+}
+
+// UnmarshalText sets the side from its name in panel layout JSON.
+func (d *DockSide) UnmarshalText(text []byte) error {
+	for side, name := range dockSideNames {
+		if name == string(text) {
+			*d = DockSide(side)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown dock side %q", text)
+}
+
+// panelEdge is a set of panel edges being resized.
+type panelEdge int
+
+const (
+	edgeLeft panelEdge = 1 << iota
+	edgeRight
+	edgeTop
+	edgeBottom
+)
+
+// panelInfo records a panel drawn this frame. Next frame, panels look up the tab group
+// they joined and the panels they are dropped on in these records.
+type panelInfo struct {
+	id    ID
+	label string
+	state *PanelState
+	host  ID  // the panel the tab group is drawn for; id for a panel on its own
+	w, h  int // the size of the panel when floating
+}
+
+// Panel renders a draggable panel with a title bar.
+// The label is used for both the title and widget identification.
+// Returns true if the panel's content should be drawn: it is expanded and, in a tab
+// group, its tab is selected.
+// The state parameter holds position, size and collapse state.
+//
+// Widgets added with the Add* methods after an expanded Panel flow down the panel body
+// until EndPanel, the next Panel or End. Pass 0 for w or h to size the panel to the
+// content laid out in it on the previous frame.
+//
+// Clicking a panel brings it to the front. Floating panels are resized by dragging their
+// right and bottom edges or the grip in their corner.
+//
+// Dropping a panel near a screen edge docks it there, filling the edge; dragging its
+// title bar undocks it. Dropping a panel on the title bar of another joins that panel's
+// tab group: the group is drawn as one panel, at the place of the panel dropped on, with
+// a tab for each member, and only the panel of the selected tab draws its content.
+// Dragging a tab out of the title bar takes its panel out of the group. A panel dropped
+// on an edge that already has a panel docked joins that panel's group.
+func (c *Context) Panel(label string, state *PanelState, w, h int) bool {
+	c.EndPanel()
+
+	id := c.GetID(label + "_panel")
+	titleBarHeight := c.style.TitleBarHeight
+	fontSize := c.style.FontSize
+
+	switch {
+	case state.W > 0:
+		w = state.W
+	case w <= 0:
+		w = max(state.contentW+2*c.style.Padding, int(c.renderer.MeasureText(label, fontSize))+50)
+	}
+	switch {
+	case state.H > 0:
+		h = state.H
+	case h <= 0:
+		h = titleBarHeight + state.contentH + 2*c.style.Padding
+	}
+	self := panelInfo{id: id, label: label, state: state, host: id, w: w, h: h}
+
+	// The members of a tab group are drawn at the place of the panel they joined, the host
+	pid, host := id, self
+	if hi, ok := c.prevHostPanel(state.TabOf); ok && hi.id != id {
+		pid, host = hi.id, hi
+		w, h = hi.w, hi.h
+		self.host = pid
+	}
+	c.panels = append(c.panels, self)
+
+	tabs := c.panelTabs(host, self)
+	active := host
+	for _, t := range tabs {
+		if t.label == host.state.ActiveTab {
+			active = t
+		}
+	}
+	if active.id != id {
+		return false // another tab of the group draws the panel
+	}
+	hs := host.state
+
+	sw, sh := c.renderer.ScreenSize()
+	floatW, floatH := w, h
+	x, y := hs.X, hs.Y
+	if hs.Dock != DockNone {
+		x, y, w, h = dockRect(hs.Dock, w, h, sw, sh)
+	}
+
+	actualHeight := h
+	if hs.Collapsed {
+		actualHeight = titleBarHeight
+	}
+
+	if c.panelZ(pid) < 0 {
+		c.panelOrder = append(c.panelOrder, pid)
+	}
+	c.registerPanelBounds(pid, x, y, w, actualHeight)
+	c.beginLayer()
+	c.deferred().panel = pid
+
+	mx, my := c.input.MousePosition()
+	pressed := c.input.IsButtonJustPressed(input.MouseButtonLeft)
+
+	blocked := c.isBlockedByPanelAbove(pid, mx, my)
+	if !blocked && pointInRect(mx, my, x, y, w, actualHeight) &&
+		(pressed || c.input.IsButtonJustPressed(input.MouseButtonRight)) {
+		c.raisePanel(pid)
+	}
+	if !hs.Collapsed {
+		c.resizePanel(pid, hs, blocked, x, y, w, h, mx, my)
+	}
+	if hs.resizing != 0 {
+		pressed = false // the press grabbed an edge, not the title bar
+	}
+
+	collapseButtonX := x + w - 30
+	collapseButtonW := 28
+	collapseHovered := !blocked && pointInRect(mx, my, collapseButtonX, y, collapseButtonW, titleBarHeight)
+
+	titleHovered := !blocked && pointInRect(mx, my, x, y, w-collapseButtonW, titleBarHeight)
+
+	if titleHovered || collapseHovered {
+		c.setHot(pid)
+	}
+
+	if collapseHovered && pressed {
+		hs.Collapsed = !hs.Collapsed
+	}
+
+	// Tabs of a group replace the title
+	tabHovered := -1
+	if len(tabs) > 1 {
+		tabX := x + 4
+		for i, t := range tabs {
+			tabW := int(c.renderer.MeasureText(t.label, fontSize)) + 20
+			if titleHovered && pointInRect(mx, my, tabX, y, tabW, titleBarHeight) {
+				tabHovered = i
+			}
+			tabX += tabW + 2
+		}
+	}
+	tabPressed := tabHovered >= 0 && pressed
+	if tabPressed {
+		t := tabs[tabHovered]
+		hs.ActiveTab = t.label
+		if t.id != pid {
+			c.tabDragID, c.tabDragX, c.tabDragY = t.id, mx, my
+		}
+	}
+	c.detachTab(tabs, host, mx, my)
+
+	if titleHovered && pressed && !(tabPressed && tabs[tabHovered].id != pid) {
+		c.setActive(pid)
+		if hs.Dock != DockNone {
+			// Undock with the mouse over the title bar of the floating panel
+			hs.Dock = DockNone
+			hs.X = mx - min(mx-x, floatW/2)
+			hs.Y = my - titleBarHeight/2
+		}
+		hs.dragging = true
+		hs.dragOffX = mx - hs.X
+		hs.dragOffY = my - hs.Y
+	}
+
+	if hs.dragging {
+		if c.input.IsButtonPressed(input.MouseButtonLeft) {
+			hs.X = mx - hs.dragOffX
+			hs.Y = my - hs.dragOffY
+			hs.X = max(min(hs.X, sw-floatW), 0)
+			hs.Y = max(min(hs.Y, sh-titleBarHeight), 0)
+			c.previewPanelDrop(pid, len(tabs) > 1, floatW, floatH, mx, my)
+		} else {
+			hs.dragging = false
+			c.clearActive()
+			c.dropPanel(pid, len(tabs) > 1, host, mx, my)
+		}
+	}
+
+	titleBgColor := c.color(ColorPanelTitle)
+	if c.isActive(pid) || titleHovered {
+		titleBgColor = c.color(ColorPanelTitleHovered)
+	}
+
+	rounding := c.style.PanelRounding
+	if hs.Dock != DockNone {
+		rounding = 0
+	}
+	c.roundedRect(x, y, w, actualHeight, rounding, c.color(ColorPanel))
+
+	c.roundedRect(x, y, w, titleBarHeight, rounding, titleBgColor)
+	if !hs.Collapsed && rounding > 0 {
+		c.filledRect(x, y+titleBarHeight-rounding, w, rounding, titleBgColor)
+	}
+
+	if len(tabs) > 1 {
+		c.drawPanelTabs(tabs, id, tabHovered, x, y, w-collapseButtonW)
+	} else {
+		c.text(label, x+10, y+(titleBarHeight-int(fontSize))/2+3, fontSize, c.color(ColorPanelTitleText))
+	}
+
+	indicatorX := x + w - 20
+	indicatorY := y + (titleBarHeight-8)/2
+	indicatorColor := c.color(ColorPanelIndicator)
+	if collapseHovered {
+		indicatorColor = c.color(ColorPanelIndicatorHovered)
+	}
+	if hs.Collapsed {
+		c.filledTriangle(indicatorX, indicatorY, indicatorX, indicatorY+8, indicatorX+6, indicatorY+4, indicatorColor)
+		c.ClearCurrentPanel()
+		c.endLayer()
+		return false
+	}
+
+	c.filledTriangle(indicatorX, indicatorY, indicatorX+8, indicatorY, indicatorX+4, indicatorY+6, indicatorColor)
+	if hs.Dock == DockNone {
+		gripColor := c.color(ColorPanelIndicator)
+		if hs.resizing != 0 {
+			gripColor = c.color(ColorPanelIndicatorHovered)
+		}
+		gx, gy := x+w-2, y+h-2
+		c.filledTriangle(gx, gy-resizeGripSize+2, gx, gy, gx-resizeGripSize+2, gy, gripColor)
+	}
+	c.SetCurrentPanel(pid)
+	c.beginPanelLayout(state, x, y+titleBarHeight, w, h-titleBarHeight)
+	return true
+}
+
+// dockRect returns the rectangle of a panel of the given floating size docked into side.
+func dockRect(side DockSide, w, h, sw, sh int) (x, y, width, height int) {
+	switch side {
+	case DockLeft:
+		return 0, 0, w, sh
+	case DockRight:
+		return sw - w, 0, w, sh
+	case DockTop:
+		return 0, 0, sw, h
+	default:
+		return 0, sh - h, sw, h
+	}
+}
+
+// dockSideAt returns the screen edge a panel dropped at (mx, my) docks into.
+func dockSideAt(mx, my, sw, sh int) DockSide {
+	switch {
+	case mx < dockMargin:
+		return DockLeft
+	case mx >= sw-dockMargin:
+		return DockRight
+	case my < dockMargin:
+		return DockTop
+	case my >= sh-dockMargin:
+		return DockBottom
+	}
+	return DockNone
+}
+
+// panelZ returns the place of a panel in the z-order, from 0 at the back, or -1 if it is
+// not a panel drawn this frame.
+func (c *Context) panelZ(id ID) int {
+	for i, p := range c.panelOrder {
+		if p == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// raisePanel brings a panel to the front.
+func (c *Context) raisePanel(id ID) {
+	if z := c.panelZ(id); z >= 0 {
+		c.panelOrder = append(c.panelOrder[:z], c.panelOrder[z+1:]...)
+	}
+	c.panelOrder = append(c.panelOrder, id)
+}
+
+// prunePanelOrder drops the panels not drawn this frame from the z-order.
+func (c *Context) prunePanelOrder() {
+	order := c.panelOrder[:0]
+	for _, id := range c.panelOrder {
+		for _, pb := range c.panelBounds {
+			if pb.id == id && !pb.popup {
+				order = append(order, id)
+				break
+			}
+		}
+	}
+	c.panelOrder = order
+}
+
+// prevHostPanel returns the panel with the given label drawn last frame, if it was drawn
+// on its own or as the host of a tab group.
+func (c *Context) prevHostPanel(label string) (panelInfo, bool) {
+	if label == "" {
+		return panelInfo{}, false
+	}
+	for _, p := range c.prevPanels {
+		if p.label == label && p.host == p.id && p.state.TabOf == "" {
+			return p, true
+		}
+	}
+	return panelInfo{}, false
+}
+
+// panelTabs returns the members of the tab group of host: the panels drawn last frame
+// that are in it now, and self.
+func (c *Context) panelTabs(host, self panelInfo) []panelInfo {
+	var tabs []panelInfo
+	seen := false
+	for _, p := range c.prevPanels {
+		if p.id == host.id || p.state.TabOf == host.label {
+			if p.id == self.id {
+				p = self
+				seen = true
+			}
+			tabs = append(tabs, p)
+		}
+	}
+	if !seen {
+		tabs = append(tabs, self)
+	}
+	return tabs
+}
+
+// detachTab takes the panel of the pressed tab out of its group once the tab has been
+// dragged far enough, and starts dragging that panel.
+func (c *Context) detachTab(tabs []panelInfo, host panelInfo, mx, my int) {
+	if c.tabDragID == 0 {
+		return
+	}
+	if !c.input.IsButtonPressed(input.MouseButtonLeft) {
+		c.tabDragID = 0
+		return
+	}
+	if max(mx-c.tabDragX, c.tabDragX-mx, my-c.tabDragY, c.tabDragY-my) < tabDetachDistance {
+		return
+	}
+	for _, t := range tabs {
+		if t.id != c.tabDragID {
+			continue
+		}
+		c.tabDragID = 0
+		if t.id == host.id {
+			return
+		}
+		s := t.state
+		s.TabOf = ""
+		s.Dock = DockNone
+		s.X = mx - min(t.w/2, int(c.renderer.MeasureText(t.label, c.style.FontSize))/2+10)
+		s.Y = my - c.style.TitleBarHeight/2
+		s.dragging = true
+		s.dragOffX, s.dragOffY = mx-s.X, my-s.Y
+		host.state.ActiveTab = host.label
+		c.raisePanel(t.id)
+		c.setActive(t.id)
+	}
+}
+
+// panelDropTarget returns the panel whose title bar is at (mx, my), to join its tab group.
+func (c *Context) panelDropTarget(pid ID, mx, my int) (panelInfo, bool) {
+	var target ID
+	z := -1
+	for _, pb := range c.prevPanelBounds {
+		if pb.popup || pb.id == pid || !pointInRect(mx, my, pb.x, pb.y, pb.w, c.style.TitleBarHeight) {
+			continue
+		}
+		if pz := c.panelZ(pb.id); pz > z {
+			target, z = pb.id, pz
+		}
+	}
+	for _, p := range c.prevPanels {
+		if p.id == target && p.host == p.id && p.state.TabOf == "" {
+			return p, true
+		}
+	}
+	return panelInfo{}, false
+}
+
+// dockedPanel returns a panel other than pid docked into side last frame.
+func (c *Context) dockedPanel(side DockSide, pid ID) (panelInfo, bool) {
+	for _, p := range c.prevPanels {
+		if p.id != pid && p.host == p.id && p.state.TabOf == "" && p.state.Dock == side {
+			return p, true
+		}
+	}
+	return panelInfo{}, false
+}
+
+// previewPanelDrop shows where the panel being dragged goes if it is dropped at (mx, my).
+// A group of tabs can be docked but not join another group.
+func (c *Context) previewPanelDrop(pid ID, group bool, w, h, mx, my int) {
+	previewColor := c.color(ColorDockPreview)
+	if !group {
+		if t, ok := c.panelDropTarget(pid, mx, my); ok {
+			for _, pb := range c.prevPanelBounds {
+				if pb.id == t.id {
+					c.deferOverlay(func() { c.filledRect(pb.x, pb.y, pb.w, c.style.TitleBarHeight, previewColor) })
+				}
+			}
+			return
+		}
+	}
+	sw, sh := c.renderer.ScreenSize()
+	if side := dockSideAt(mx, my, sw, sh); side != DockNone {
+		dx, dy, dw, dh := dockRect(side, w, h, sw, sh)
+		c.deferOverlay(func() { c.filledRect(dx, dy, dw, dh, previewColor) })
+	}
+}
+
+// dropPanel docks the panel dropped at (mx, my) or adds it to a tab group.
+func (c *Context) dropPanel(pid ID, group bool, p panelInfo, mx, my int) {
+	join := func(t panelInfo) {
+		p.state.TabOf = t.label
+		p.state.Dock = DockNone
+		t.state.ActiveTab = p.label
+	}
+	if !group {
+		if t, ok := c.panelDropTarget(pid, mx, my); ok {
+			join(t)
+			return
+		}
+	}
+	sw, sh := c.renderer.ScreenSize()
+	side := dockSideAt(mx, my, sw, sh)
+	if side == DockNone {
+		return
+	}
+	if t, ok := c.dockedPanel(side, pid); ok && !group {
+		join(t)
+		return
+	}
+	p.state.Dock = side
+}
+
+// resizePanel resizes a panel by the edges the mouse drags: the right and bottom edges
+// of a floating panel, or the edge of a docked panel facing the middle of the screen.
+func (c *Context) resizePanel(pid ID, s *PanelState, blocked bool, x, y, w, h, mx, my int) {
+	minH := c.style.TitleBarHeight + 2*c.style.Padding
+	if s.resizing == 0 && !blocked {
+		var edges panelEdge
+		switch s.Dock {
+		case DockNone:
+			if pointInRect(mx, my, x+w-resizeGripSize, y+h-resizeGripSize, resizeGripSize, resizeGripSize) {
+				edges = edgeRight | edgeBottom
+			} else if pointInRect(mx, my, x+w-resizeHandleSize, y+c.style.TitleBarHeight, resizeHandleSize, h-c.style.TitleBarHeight) {
+				edges = edgeRight
+			} else if pointInRect(mx, my, x, y+h-resizeHandleSize, w, resizeHandleSize) {
+				edges = edgeBottom
+			}
+		case DockLeft:
+			if pointInRect(mx, my, x+w-resizeHandleSize, y+c.style.TitleBarHeight, resizeHandleSize, h-c.style.TitleBarHeight) {
+				edges = edgeRight
+			}
+		case DockRight:
+			if pointInRect(mx, my, x, y+c.style.TitleBarHeight, resizeHandleSize, h-c.style.TitleBarHeight) {
+				edges = edgeLeft
+			}
+		case DockTop:
+			if pointInRect(mx, my, x, y+h-resizeHandleSize, w, resizeHandleSize) {
+				edges = edgeBottom
+			}
+		case DockBottom:
+			if pointInRect(mx, my, x, y, w, resizeHandleSize) {
+				edges = edgeTop
+			}
+		}
+		if edges != 0 {
+			c.setCursor(edgeCursor(edges))
+		}
+		if edges != 0 && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+			s.resizing = edges
+			s.dragOffX = x + w - mx
+			if edges&edgeLeft != 0 {
+				s.dragOffX = mx - x
+			}
+			s.dragOffY = y + h - my
+			if edges&edgeTop != 0 {
+				s.dragOffY = my - y
+			}
+			c.setActive(pid)
+		}
+	}
+
+	if s.resizing == 0 {
+		return
+	}
+	if !c.input.IsButtonPressed(input.MouseButtonLeft) {
+		s.resizing = 0
+		c.clearActive()
+		return
+	}
+	c.setHot(pid)
+	c.setCursor(edgeCursor(s.resizing))
+	switch {
+	case s.resizing&edgeRight != 0:
+		s.W = max(mx+s.dragOffX-x, minPanelWidth)
+	case s.resizing&edgeLeft != 0:
+		s.W = max(x+w-(mx-s.dragOffX), minPanelWidth)
+	}
+	switch {
+	case s.resizing&edgeBottom != 0:
+		s.H = max(my+s.dragOffY-y, minH)
+	case s.resizing&edgeTop != 0:
+		s.H = max(y+h-(my-s.dragOffY), minH)
+	}
+}
+
+// edgeCursor returns the cursor shown over panel edges being resized.
+func edgeCursor(edges panelEdge) input.CursorShape {
+	switch {
+	case edges&(edgeLeft|edgeRight) != 0 && edges&(edgeTop|edgeBottom) != 0:
+		return input.CursorResizeNWSE
+	case edges&(edgeLeft|edgeRight) != 0:
+		return input.CursorResizeH
+	}
+	return input.CursorResizeV
+}
+
+// drawPanelTabs draws the tabs of a tab group along the title bar, within width w.
+func (c *Context) drawPanelTabs(tabs []panelInfo, active ID, hovered, x, y, w int) {
+	titleBarHeight := c.style.TitleBarHeight
+	fontSize := c.style.FontSize
+	rounding := c.style.FrameRounding
+
+	c.pushClip(x, y, w, titleBarHeight)
+	tabX := x + 4
+	for i, t := range tabs {
+		tabW := int(c.renderer.MeasureText(t.label, fontSize)) + 20
+		bgColor := c.color(ColorTab)
+		switch {
+		case t.id == active:
+			bgColor = c.color(ColorTabActive)
+		case i == hovered:
+			bgColor = c.color(ColorTabHovered)
+		}
+		tabY, tabH := y+4, titleBarHeight-4
+		c.roundedRect(tabX, tabY, tabW, tabH, rounding, bgColor)
+		c.filledRect(tabX, tabY+tabH-rounding, tabW, rounding, bgColor)
+		c.text(t.label, tabX+10, tabY+(tabH-int(fontSize))/2+1, fontSize, c.color(ColorPanelTitleText))
+		tabX += tabW + 2
+	}
+	c.popClip()
+}
+
+// MarshalPanelLayout returns the layout of the given panels, keyed by label, as JSON:
+//
+//	{"Inspector": {"x": 0, "y": 0, "w": 300, "dock": "left"}, "Log": {"x": 0, "y": 0, "tab_of": "Inspector"}}
+func MarshalPanelLayout(panels map[string]*PanelState) ([]byte, error) {
+	data, err := json.MarshalIndent(panels, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode panel layout: %w", err)
+	}
+	return data, nil
+}
+
+// SavePanelLayoutFile writes the layout of the given panels, keyed by label, to a JSON file.
+func SavePanelLayoutFile(path string, panels map[string]*PanelState) error {
+	data, err := MarshalPanelLayout(panels)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write panel layout file: %w", err)
+	}
+	return nil
+}
+
+// LoadPanelLayout sets the layout of the given panels, keyed by label, from JSON written
+// by MarshalPanelLayout. Panels in the JSON but not in the map are added to it; panels
+// missing from the JSON keep their state.
+func LoadPanelLayout(data []byte, panels map[string]*PanelState) error {
+	var layout map[string]PanelState
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return fmt.Errorf("failed to parse panel layout: %w", err)
+	}
+	for label, s := range layout {
+		if state := panels[label]; state != nil {
+			*state = s
+		} else {
+			panels[label] = &s
+		}
+	}
+	return nil
+}
+
+// LoadPanelLayoutFile sets the layout of the given panels from a JSON file written by
+// SavePanelLayoutFile.
+func LoadPanelLayoutFile(path string, panels map[string]*PanelState) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read panel layout file: %w", err)
+	}
+	return LoadPanelLayout(data, panels)
+}
@@ -0,0 +1,231 @@
+package gui
+
+import (
+	"time"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+const (
+	maxComboRows = 8                      // items a combo popup shows before it scrolls
+	tooltipDelay = 500 * time.Millisecond // how long the mouse rests on a widget before its tooltip shows
+)
+
+// deferOverlay queues fn to run at End, after every other widget of the frame, so what
+// it draws appears on top.
+func (c *Context) deferOverlay(fn func()) {
+	c.overlays = append(c.overlays, fn)
+}
+
+// drawOverlays runs the overlays queued this frame.
+func (c *Context) drawOverlays() {
+	// Close the popup of a combo that was not drawn this frame, e.g. in a collapsed panel
+	if !c.popupSeen {
+		c.popupID = 0
+	}
+	c.popupSeen = false
+
+	for _, fn := range c.overlays {
+		fn()
+		c.layouts = c.layouts[:0]
+	}
+	c.overlays = c.overlays[:0]
+	c.ClearCurrentPanel()
+}
+
+// registerPopupBounds records a popup's bounds. Next frame, widgets beneath the popup
+// ignore the mouse.
+func (c *Context) registerPopupBounds(id ID, x, y, w, h int) {
+	c.panelBounds = append(c.panelBounds, panelBound{id: id, x: x, y: y, w: w, h: h, popup: true})
+}
+
+// openCombo opens the popup of a combo with the given number of items, scrolled to
+// the selected item.
+func (c *Context) openCombo(id ID, selected, items int) {
+	rowH := c.rowHeight()
+	c.popupID = id
+	c.menuFromBar = false
+	c.popupCursor = min(max(selected, 0), max(items-1, 0))
+	// Let BeginScroll scroll to the cursor before the content has been measured
+	c.popupScroll = ScrollState{contentH: items * rowH}
+	scrollIntoView(&c.popupScroll.ScrollY, c.popupCursor*rowH, rowH, min(items, maxComboRows)*rowH)
+}
+
+// comboPopup draws the open popup of a combo. anchor is the combo's rectangle, which
+// clicks do not close the popup on since the combo toggles it itself.
+func (c *Context) comboPopup(label string, id ID, items []string, selected, x, y, w, h int, anchor [4]int) {
+	c.registerPopupBounds(id, x, y, w, h)
+	c.SetCurrentPanel(id)
+
+	c.roundedRect(x, y, w, h, c.style.FrameRounding, c.color(ColorPopup))
+	isSelected := func(i int) bool { return i == selected }
+	pressed := c.listRows(label+"_popup", id, items, &c.popupScroll, x+2, y+2, w-4, h-4, isSelected, c.popupCursor)
+	if pressed >= 0 {
+		c.pickedID, c.pickedItem = id, pressed
+		c.popupID = 0
+		return
+	}
+
+	mx, my := c.input.MousePosition()
+	if c.input.IsButtonJustPressed(input.MouseButtonLeft) &&
+		!pointInRect(mx, my, x, y, w, h) && !pointInRect(mx, my, anchor[0], anchor[1], anchor[2], anchor[3]) {
+		c.popupID = 0
+	}
+}
+
+// OpenPopupModal opens the modal popup with the given name, which BeginPopupModal then
+// draws until it is closed. Any open menu or combo popup is closed and focus is cleared.
+func (c *Context) OpenPopupModal(name string) {
+	c.modalID = c.GetID(name + "_modal")
+	c.popupID = 0
+	c.focusedID = 0
+}
+
+// CloseCurrentPopup closes the open modal popup, or the open menu when called between
+// Menu or BeginContextMenu and EndMenu.
+func (c *Context) CloseCurrentPopup() {
+	if c.menu != nil {
+		c.popupID = 0
+		return
+	}
+	c.modalID = 0
+}
+
+// IsPopupModalOpen returns true if the modal popup with the given name is open.
+func (c *Context) IsPopupModalOpen(name string) bool {
+	return c.modalID != 0 && c.modalID == c.GetID(name+"_modal")
+}
+
+// BeginPopupModal draws the modal popup with the given name while it is open and returns
+// true if it is. The popup is centered on the screen above a dimmed background with the
+// name in its title bar, and captures the input: widgets outside it ignore the mouse and
+// tab only moves focus between the popup's widgets. Widgets added with the Add* methods
+// until EndPopupModal flow down its body; pass 0 for w or h to size it to its content.
+// Escape closes it.
+//
+//	if ctx.AddButton("Delete") {
+//	    ctx.OpenPopupModal("Delete entity?")
+//	}
+//	if ctx.BeginPopupModal("Delete entity?", 0, 0) {
+//	    if ctx.AddButton("Delete") {
+//	        deleteEntity()
+//	        ctx.CloseCurrentPopup()
+//	    }
+//	    ctx.SameLine()
+//	    if ctx.AddButton("Cancel") {
+//	        ctx.CloseCurrentPopup()
+//	    }
+//	    ctx.EndPopupModal()
+//	}
+func (c *Context) BeginPopupModal(name string, w, h int) bool {
+	id := c.GetID(name + "_modal")
+	if c.modalID != id || c.inModal {
+		return false
+	}
+	c.inModal = true
+
+	// Drawn into a layer so it covers the widgets added after it this frame
+	c.beginLayer()
+	sw, sh := c.renderer.ScreenSize()
+	c.registerPopupBounds(id, 0, 0, sw, sh)
+	c.SetCurrentPanel(id)
+	c.filledRect(0, 0, sw, sh, c.color(ColorModalDim))
+
+	padding := c.style.Padding
+	titleH := c.style.TitleBarHeight
+	if w <= 0 {
+		w = max(c.modalContentW+2*padding, int(c.renderer.MeasureText(name, c.style.FontSize))+2*padding)
+	}
+	if h <= 0 {
+		h = titleH + c.modalContentH + 2*padding
+	}
+	x, y := (sw-w)/2, (sh-h)/2
+
+	rounding := c.style.PanelRounding
+	c.roundedRectOutline(x, y, w, h, rounding, 1, c.color(ColorPanel), c.color(ColorBorder))
+	c.roundedRect(x, y, w, titleH, rounding, c.color(ColorPanelTitle))
+	c.filledRect(x, y+titleH-rounding, w, rounding, c.color(ColorPanelTitle))
+	fontSize := c.style.FontSize
+	c.text(name, x+padding, y+(titleH-int(fontSize))/2, fontSize, c.color(ColorPanelTitleText))
+
+	c.currentLayout()
+	c.pushLayout(&layout{direction: directionColumn, root: true}, x, y+titleH, w, h-titleH, padding)
+	c.modalLayout = c.layouts[len(c.layouts)-1]
+	return true
+}
+
+// EndPopupModal closes the body of the popup opened by BeginPopupModal, along with any
+// layouts left open inside it.
+func (c *Context) EndPopupModal() {
+	if !c.inModal {
+		return
+	}
+	c.inModal = false
+
+	for i := len(c.layouts) - 1; i >= 0; i-- {
+		if c.layouts[i] == c.modalLayout {
+			c.modalContentW, c.modalContentH = c.modalLayout.usedW, c.modalLayout.usedH
+			c.layouts = c.layouts[:i]
+			break
+		}
+	}
+	c.modalLayout = nil
+
+	if c.input.IsKeyJustPressed(input.KeyEscape) && !c.escapeUsed {
+		c.modalID = 0
+	}
+	c.endLayer()
+}
+
+// Tooltip shows text in a box next to the mouse once it has rested on the last widget
+// for half a second. Call it right after the widget:
+//
+//	ctx.AddButton("Bake")
+//	ctx.Tooltip("Bakes the lightmaps of the open scene")
+//
+// Tooltips follow the widgets that take focus, such as buttons, sliders and tree nodes;
+// pressing a mouse button hides the tooltip until the mouse rests again.
+func (c *Context) Tooltip(text string) {
+	id := c.lastItemID
+	if id == 0 || c.hotID != id {
+		return
+	}
+	c.tooltipSeen = true
+	pressed := c.input.IsButtonPressed(input.MouseButtonLeft) || c.input.IsButtonPressed(input.MouseButtonRight)
+	if c.tooltipID != id || pressed {
+		c.tooltipID = id
+		c.tooltipStart = c.frameTime
+		return
+	}
+	if c.frameTime.Sub(c.tooltipStart) >= tooltipDelay {
+		c.tooltipText = text
+	}
+}
+
+// drawTooltip draws the tooltip requested this frame, if any, above everything else.
+func (c *Context) drawTooltip() {
+	if !c.tooltipSeen {
+		c.tooltipID = 0
+	}
+	c.tooltipSeen = false
+	if c.tooltipText == "" {
+		return
+	}
+	text := c.tooltipText
+	c.tooltipText = ""
+
+	fontSize := c.style.FontSize
+	w, h := int(c.renderer.MeasureText(text, fontSize))+16, int(fontSize)+12
+	mx, my := c.input.MousePosition()
+	sw, sh := c.renderer.ScreenSize()
+	x, y := mx+12, my+20
+	if x+w > sw {
+		x = max(sw-w, 0)
+	}
+	if y+h > sh {
+		y = max(my-h-4, 0)
+	}
+
+	c.roundedRectOutline(x, y, w, h, c.style.FrameRounding, 1, c.color(ColorPopup), c.color(ColorBorder))
+	c.text(text, x+8, y+(h-int(fontSize))/2, fontSize, c.color(ColorText))
+}
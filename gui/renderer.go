@@ -0,0 +1,106 @@
+package gui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/dfirebaugh/hlg"
+)
+
+// Renderer draws the widgets of a Context and measures their text. A Context draws
+// with hlg through DefaultRenderer unless SetRenderer replaces it, for example with
+// the recorder of the guitest package to test widgets without a window.
+type Renderer interface {
+	BeginDraw()
+	EndDraw()
+	ScreenSize() (w, h int)
+
+	FilledRect(x, y, w, h int, col color.Color)
+	FilledTriangle(x1, y1, x2, y2, x3, y3 int, col color.Color)
+	RoundedRect(x, y, w, h, radius int, col color.Color)
+	RoundedRectOutline(x, y, w, h, radius, outlineW int, fill, outline color.Color)
+	Text(s string, x, y int, fontSize float32, col color.Color)
+	// Image draws the src rectangle of a texture, in texture pixels, stretched over
+	// the given rectangle.
+	Image(tex *hlg.Texture, src image.Rectangle, x, y, w, h int)
+	PushClipRect(x, y, w, h int)
+	PopClipRect()
+
+	// SetFont makes font the one text is drawn and measured with.
+	SetFont(font *hlg.Font)
+	MeasureText(s string, fontSize float32) float32
+	// TextIndexAtPoint returns the byte index of the caret position closest to (x, y),
+	// relative to where the text is drawn.
+	TextIndexAtPoint(s string, fontSize, x, y float32) int
+	// TextCaretRect returns the caret rectangle before the byte index, relative to
+	// where the text is drawn.
+	TextCaretRect(s string, fontSize float32, index int) (x, y, w, h float32)
+}
+
+// DefaultRenderer draws with hlg's batched drawing functions.
+type DefaultRenderer struct{}
+
+func (DefaultRenderer) BeginDraw() { hlg.BeginDraw() }
+func (DefaultRenderer) EndDraw()   { hlg.EndDraw() }
+
+func (DefaultRenderer) ScreenSize() (w, h int) { return hlg.GetScreenSize() }
+
+func (DefaultRenderer) FilledRect(x, y, w, h int, col color.Color) {
+	hlg.FilledRect(x, y, w, h, col)
+}
+
+func (DefaultRenderer) FilledTriangle(x1, y1, x2, y2, x3, y3 int, col color.Color) {
+	hlg.FilledTriangle(x1, y1, x2, y2, x3, y3, col)
+}
+
+func (DefaultRenderer) RoundedRect(x, y, w, h, radius int, col color.Color) {
+	hlg.RoundedRect(x, y, w, h, radius, col)
+}
+
+func (DefaultRenderer) RoundedRectOutline(x, y, w, h, radius, outlineW int, fill, outline color.Color) {
+	hlg.RoundedRectOutline(x, y, w, h, radius, outlineW, fill, outline)
+}
+
+func (DefaultRenderer) Text(s string, x, y int, fontSize float32, col color.Color) {
+	hlg.Text(s, x, y, fontSize, col)
+}
+
+// Image draws with the texture itself, changing its clip, size and position. Rendering
+// a texture flushes hlg's batch first, so it keeps its place in the draw order and the
+// current clip rect.
+func (DefaultRenderer) Image(tex *hlg.Texture, src image.Rectangle, x, y, w, h int) {
+	tex.Clip(float32(src.Min.X), float32(src.Min.Y), float32(src.Max.X), float32(src.Max.Y))
+	tex.Resize(float32(w), float32(h))
+	tex.Move(float32(x), float32(y))
+	tex.Render()
+}
+
+func (DefaultRenderer) PushClipRect(x, y, w, h int) { hlg.PushClipRect(x, y, w, h) }
+func (DefaultRenderer) PopClipRect()                { hlg.PopClipRect() }
+
+// SetFont makes font hlg's default font. Since the primitive buffer samples one
+// primary atlas, the font's atlas becomes the active one.
+func (DefaultRenderer) SetFont(font *hlg.Font) {
+	if font != hlg.GetDefaultFont() {
+		hlg.SetDefaultFont(font)
+		font.SetAsActiveAtlas()
+	}
+}
+
+func (DefaultRenderer) MeasureText(s string, fontSize float32) float32 {
+	return hlg.MeasureText(s, fontSize)
+}
+
+func (DefaultRenderer) TextIndexAtPoint(s string, fontSize, x, y float32) int {
+	return hlg.TextIndexAtPoint(s, fontSize, x, y)
+}
+
+func (DefaultRenderer) TextCaretRect(s string, fontSize float32, index int) (x, y, w, h float32) {
+	return hlg.TextCaretRect(s, fontSize, index)
+}
+
+// SetRenderer sets what the Context draws with and measures text with.
+func (c *Context) SetRenderer(r Renderer) {
+	c.renderer = r
+	c.applyStyleFont()
+}
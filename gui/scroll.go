@@ -0,0 +1,220 @@
+package gui
+
+import "github.com/dfirebaugh/hlg/pkg/input"
+
+const (
+	wheelStep    = 40 // pixels scrolled by one notch of the mouse wheel
+	minThumbSize = 16 // shortest a scrollbar thumb gets for very long content
+)
+
+// scrollRegion is a region opened by BeginScroll.
+type scrollRegion struct {
+	label        string
+	state        *ScrollState
+	x, y, w, h   int // the whole region, scrollbars included
+	viewW, viewH int // the part of the region the content is visible in
+}
+
+// BeginScroll opens a scroll region on the given rectangle of the screen.
+// Widgets added with the Add* methods until EndScroll flow down the region, offset by
+// the scroll position and clipped to the region. Scrollbars appear along the right and
+// bottom edges when the content is larger than the region, and the mouse wheel scrolls
+// the innermost region under the mouse; hold shift to scroll horizontally.
+// Widgets drawn at absolute coordinates are not offset, so take their position from
+// NextRect.
+//
+// To place the region in a layout, reserve its rectangle with NextRect:
+//
+//	x, y, w, h := ctx.NextRect(gui.Fill(), gui.Fixed(200))
+//	ctx.BeginScroll("log", x, y, w, h, &logScroll)
+//	for _, line := range lines {
+//	    ctx.AddLabel(line)
+//	}
+//	ctx.EndScroll()
+func (c *Context) BeginScroll(label string, x, y, w, h int, state *ScrollState) {
+	// Reserve room for the scrollbars that last frame's content needed
+	sb := c.style.ScrollbarSize
+	viewW, viewH := w, h
+	showV := state.contentH > viewH
+	if showV {
+		viewW -= sb
+	}
+	if state.contentW > viewW {
+		viewH -= sb
+		if !showV && state.contentH > viewH {
+			viewW -= sb
+		}
+	}
+	viewW, viewH = max(viewW, 0), max(viewH, 0)
+
+	state.ScrollX = clampScroll(state.ScrollX, state.contentW, viewW)
+	state.ScrollY = clampScroll(state.ScrollY, state.contentH, viewH)
+
+	region := &scrollRegion{label: label, state: state, x: x, y: y, w: w, h: h, viewW: viewW, viewH: viewH}
+	c.pushClip(x, y, viewW, viewH)
+	c.currentLayout()
+	c.pushLayout(&layout{direction: directionColumn, scroll: region}, x-state.ScrollX, y-state.ScrollY, viewW, 0, c.style.Padding)
+}
+
+// EndScroll closes the region opened by BeginScroll, along with any rows and columns
+// left open inside it, then handles the mouse wheel and draws the scrollbars.
+func (c *Context) EndScroll() {
+	var l *layout
+	for len(c.layouts) > 0 && l == nil {
+		top := c.layouts[len(c.layouts)-1]
+		if top.root || top.panel != nil {
+			return
+		}
+		c.layouts = c.layouts[:len(c.layouts)-1]
+		if top.scroll != nil {
+			l = top
+		}
+	}
+	if l == nil {
+		return
+	}
+	c.popClip()
+
+	r := l.scroll
+	state := r.state
+	state.contentW = l.usedW + 2*c.style.Padding
+	state.contentH = l.usedH + 2*c.style.Padding
+	maxX := max(state.contentW-r.viewW, 0)
+	maxY := max(state.contentH-r.viewH, 0)
+
+	mx, my := c.input.MousePosition()
+	if pointInRect(mx, my, r.x, r.y, r.w, r.h) && !c.isInputBlocked(mx, my) {
+		wx, wy := c.wheelX, c.wheelY
+		if wx == 0 && c.shiftHeld {
+			wx, wy = wy, 0
+		}
+		// Leave the wheel to enclosing regions when this one cannot scroll that way
+		consumed := false
+		if wy != 0 && maxY > 0 {
+			state.ScrollY = min(max(state.ScrollY-int(wy*wheelStep), 0), maxY)
+			consumed = true
+		}
+		if wx != 0 && maxX > 0 {
+			state.ScrollX = min(max(state.ScrollX-int(wx*wheelStep), 0), maxX)
+			consumed = true
+		}
+		if consumed {
+			c.wheelX, c.wheelY = 0, 0
+		}
+	}
+
+	sb := c.style.ScrollbarSize
+	if r.viewW < r.w {
+		state.ScrollY = c.scrollbar(r.label+"_vscroll", state, state.ScrollY, maxY, r.x+r.viewW, r.y, r.viewH, true)
+	}
+	if r.viewH < r.h {
+		state.ScrollX = c.scrollbar(r.label+"_hscroll", state, state.ScrollX, maxX, r.x, r.y+r.viewH, r.viewW, false)
+	}
+	if r.viewW < r.w && r.viewH < r.h {
+		c.filledRect(r.x+r.viewW, r.y+r.viewH, sb, sb, c.color(ColorScrollbar))
+	}
+}
+
+// scrollbar draws a scrollbar whose track starts at (x, y) and runs length pixels down,
+// or right when vertical is false, and returns the scroll offset after the thumb is
+// dragged. Pressing the track moves the thumb under the mouse and starts dragging it.
+func (c *Context) scrollbar(label string, state *ScrollState, offset, maxOffset, x, y, length int, vertical bool) int {
+	id := c.GetID(label)
+	sb := c.style.ScrollbarSize
+	trackW, trackH := sb, length
+	if !vertical {
+		trackW, trackH = length, sb
+	}
+
+	thumbLen := length
+	if maxOffset > 0 {
+		thumbLen = min(max(length*length/(length+maxOffset), minThumbSize), length)
+	}
+	travel := length - thumbLen
+
+	mx, my := c.input.MousePosition()
+	along := my - y
+	if !vertical {
+		along = mx - x
+	}
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, x, y, trackW, trackH)
+	thumbPos := 0
+	if maxOffset > 0 && travel > 0 {
+		thumbPos = offset * travel / maxOffset
+	}
+	thumbHovered := hovered && along >= thumbPos && along < thumbPos+thumbLen
+
+	if hovered {
+		c.setHot(id)
+	}
+
+	if hovered && maxOffset > 0 && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+		c.setActive(id)
+		state.dragOff = along - thumbPos
+		if !thumbHovered {
+			state.dragOff = thumbLen / 2
+		}
+	}
+
+	if c.isActive(id) {
+		if c.input.IsButtonPressed(input.MouseButtonLeft) {
+			if travel > 0 {
+				offset = min(max((along-state.dragOff)*maxOffset/travel, 0), maxOffset)
+				thumbPos = offset * travel / maxOffset
+			}
+		} else {
+			c.clearActive()
+		}
+	}
+
+	c.filledRect(x, y, trackW, trackH, c.color(ColorScrollbar))
+
+	thumbColor := c.color(ColorScrollbarThumb)
+	if c.isActive(id) || thumbHovered {
+		thumbColor = c.color(ColorScrollbarThumbHovered)
+	}
+	if vertical {
+		c.roundedRect(x+2, y+thumbPos+2, sb-4, thumbLen-4, (sb-4)/2, thumbColor)
+	} else {
+		c.roundedRect(x+thumbPos+2, y+2, thumbLen-4, sb-4, (sb-4)/2, thumbColor)
+	}
+
+	return offset
+}
+
+// scrollIntoView adjusts a scroll offset so that the span [pos, pos+size) is visible in
+// a view of the given length.
+func scrollIntoView(offset *int, pos, size, view int) {
+	if pos < *offset {
+		*offset = pos
+	} else if pos+size > *offset+view {
+		*offset = pos + size - view
+	}
+}
+
+// clampScroll keeps a scroll offset within content that is view pixels wide on screen.
+func clampScroll(offset, content, view int) int {
+	return min(max(offset, 0), max(content-view, 0))
+}
+
+// pushClip clips drawing to the part of the rectangle inside the current clip rect.
+// Widgets ignore the mouse outside of it.
+func (c *Context) pushClip(x, y, w, h int) {
+	if len(c.clipRects) > 0 {
+		p := c.clipRects[len(c.clipRects)-1]
+		x0, y0 := max(x, p[0]), max(y, p[1])
+		x1, y1 := min(x+w, p[0]+p[2]), min(y+h, p[1]+p[3])
+		x, y, w, h = x0, y0, max(x1-x0, 0), max(y1-y0, 0)
+	}
+	c.clipRects = append(c.clipRects, [4]int{x, y, w, h})
+	c.pushClipRect(x, y, w, h)
+}
+
+// popClip restores the clip rect that was current before the last pushClip.
+func (c *Context) popClip() {
+	if len(c.clipRects) == 0 {
+		return
+	}
+	c.clipRects = c.clipRects[:len(c.clipRects)-1]
+	c.popClipRect()
+}
@@ -1,22 +1,103 @@
 package gui
 
+import "sort"
+
 // TextInputState holds the state for a text input widget.
 // The caller owns this state and passes it to InputText calls.
 type TextInputState struct {
-	CursorPos      int
+	CursorPos      int // byte offset of the caret in the text
 	SelectionStart int
 	SelectionEnd   int
 	focused        bool
 }
 
 // PanelState holds the state for a draggable panel.
-// The caller owns this state and passes it to Panel calls.
+// The caller owns this state and passes it to Panel calls. The exported fields are the
+// panel's layout, which SavePanelLayoutFile and LoadPanelLayoutFile store as JSON.
 type PanelState struct {
-	X, Y      int
-	Collapsed bool
-	dragging  bool
-	dragOffX  int
-	dragOffY  int
+	X         int      `json:"x"`
+	Y         int      `json:"y"`
+	W         int      `json:"w,omitempty"` // size set by resizing the panel; 0 uses the size passed to Panel
+	H         int      `json:"h,omitempty"`
+	Collapsed bool     `json:"collapsed,omitempty"`
+	Dock      DockSide `json:"dock,omitempty"`       // the screen edge the panel fills, if any
+	TabOf     string   `json:"tab_of,omitempty"`     // label of the panel whose tab group this panel joined
+	ActiveTab string   `json:"active_tab,omitempty"` // label of the selected tab of this panel's tab group
+
+	dragging bool
+	resizing panelEdge // the edges being dragged
+	dragOffX int
+	dragOffY int
+
+	// Size of the content laid out in the panel last frame, for auto sizing
+	contentW int
+	contentH int
+}
+
+// ScrollState holds the scroll position of a scroll region.
+// The caller owns this state and passes it to BeginScroll calls.
+type ScrollState struct {
+	ScrollX, ScrollY int // offset of the content from the top left of the region
+
+	// Size of the content laid out in the region last frame, including padding
+	contentW int
+	contentH int
+
+	dragOff int // distance from the dragged scrollbar thumb's start to the mouse
+}
+
+// TextAreaState holds the caret, selection, scroll position and undo history of a
+// text area. The caller owns this state and passes it to TextArea calls.
+type TextAreaState struct {
+	CursorPos int // byte offset of the caret in the text
+
+	anchor        int // the end of the selection opposite the caret
+	preferredX    float32
+	hasPreferredX bool // preferredX holds the column kept while moving up and down
+	history       textHistory
+	scroll        ScrollState
+}
+
+// ListBoxState holds the selection and scroll position of a list box.
+// The caller owns this state and passes it to ListBox calls.
+type ListBoxState struct {
+	Selected    int  // the selected item of a single-select list box
+	MultiSelect bool // allow selecting several items with ctrl and shift
+	Cursor      int  // the item moved by the arrow keys
+
+	selected map[int]bool // the selected items of a multi-select list box
+	anchor   int          // the item shift-selection extends from
+	scroll   ScrollState
+}
+
+// TableState holds the column widths, sort order, selected row and scroll position of
+// a table. The caller owns this state and passes it to BeginTable calls.
+type TableState struct {
+	Selected       int  // the selected row
+	SortColumn     int  // the column the rows are sorted by
+	SortDescending bool // sort from the largest value down
+
+	initialized bool
+	widths      []int // column widths in pixels, set from the columns on first use
+	dragOff     int   // distance from the mouse to the edge of the column being resized
+	scroll      ScrollState
+}
+
+// ColumnWidth returns the width of column i in pixels, or 0 before the table is first drawn.
+func (s *TableState) ColumnWidth(i int) int {
+	if i < 0 || i >= len(s.widths) {
+		return 0
+	}
+	return s.widths[i]
+}
+
+// totalWidth returns the width of all the columns.
+func (s *TableState) totalWidth() int {
+	total := 0
+	for _, w := range s.widths {
+		total += w
+	}
+	return total
 }
 
 // HasSelection returns true if there is a text selection.
@@ -29,3 +110,79 @@ func (s *TextInputState) ClearSelection() {
 	s.SelectionStart = s.CursorPos
 	s.SelectionEnd = s.CursorPos
 }
+
+// clamp keeps the caret and selection within text and on the first byte of a
+// character, since the caller may have changed the text or set them mid-character.
+func (s *TextInputState) clamp(text string) {
+	s.CursorPos = clampToRune(text, s.CursorPos)
+	s.SelectionStart = clampToRune(text, s.SelectionStart)
+	s.SelectionEnd = clampToRune(text, s.SelectionEnd)
+}
+
+// HasSelection returns true if there is a text selection.
+func (s *TextAreaState) HasSelection() bool {
+	return s.anchor != s.CursorPos
+}
+
+// Selection returns the byte offsets of the start and end of the selected text.
+func (s *TextAreaState) Selection() (start, end int) {
+	return min(s.anchor, s.CursorPos), max(s.anchor, s.CursorPos)
+}
+
+// SetSelection selects the text between the byte offsets start and end and puts the
+// caret at end. Pass the same offset twice to move the caret without selecting.
+func (s *TextAreaState) SetSelection(start, end int) {
+	s.anchor, s.CursorPos = start, end
+	s.hasPreferredX = false
+}
+
+// IsSelected returns true if item i is selected.
+func (s *ListBoxState) IsSelected(i int) bool {
+	if !s.MultiSelect {
+		return i == s.Selected
+	}
+	return s.selected[i]
+}
+
+// SetSelected selects or deselects item i. In a single-select list box selecting an
+// item replaces the selection.
+func (s *ListBoxState) SetSelected(i int, selected bool) {
+	if !s.MultiSelect {
+		if selected {
+			s.Selected = i
+		} else if s.Selected == i {
+			s.Selected = -1
+		}
+		return
+	}
+	if s.selected == nil {
+		s.selected = make(map[int]bool)
+	}
+	if selected {
+		s.selected[i] = true
+	} else {
+		delete(s.selected, i)
+	}
+}
+
+// SelectedItems returns the selected items in ascending order.
+func (s *ListBoxState) SelectedItems() []int {
+	if !s.MultiSelect {
+		if s.Selected < 0 {
+			return nil
+		}
+		return []int{s.Selected}
+	}
+	items := make([]int, 0, len(s.selected))
+	for i := range s.selected {
+		items = append(items, i)
+	}
+	sort.Ints(items)
+	return items
+}
+
+// ClearSelection deselects every item.
+func (s *ListBoxState) ClearSelection() {
+	s.Selected = -1
+	clear(s.selected)
+}
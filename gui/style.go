@@ -0,0 +1,346 @@
+package gui
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dfirebaugh/hlg"
+)
+
+// StyleColor identifies one of the colors of a Style.
+type StyleColor int
+
+const (
+	ColorText StyleColor = iota
+	ColorFocusRing
+	ColorButton
+	ColorButtonHovered
+	ColorButtonActive
+	ColorCheckbox
+	ColorCheckboxHovered
+	ColorCheckMark
+	ColorToggle
+	ColorToggleOn
+	ColorToggleKnob
+	ColorToggleKnobHovered
+	ColorSliderTrack
+	ColorSliderFill
+	ColorSliderHandle
+	ColorSliderHandleActive
+	ColorInput
+	ColorInputFocused
+	ColorSelection
+	ColorCaret
+	ColorPanel
+	ColorPanelTitle
+	ColorPanelTitleHovered
+	ColorPanelTitleText
+	ColorPanelIndicator
+	ColorPanelIndicatorHovered
+	ColorScrollbar
+	ColorScrollbarThumb
+	ColorScrollbarThumbHovered
+	ColorItemHovered
+	ColorPopup
+	ColorHeader
+	ColorHeaderHovered
+	ColorTab
+	ColorTabHovered
+	ColorTabActive
+	ColorBorder
+	ColorModalDim
+	ColorDockPreview
+
+	// ColorCount is the number of style colors.
+	ColorCount
+)
+
+// styleColorNames are the keys of the colors in style JSON.
+var styleColorNames = [ColorCount]string{
+	ColorText:                  "text",
+	ColorFocusRing:             "focus_ring",
+	ColorButton:                "button",
+	ColorButtonHovered:         "button_hovered",
+	ColorButtonActive:          "button_active",
+	ColorCheckbox:              "checkbox",
+	ColorCheckboxHovered:       "checkbox_hovered",
+	ColorCheckMark:             "check_mark",
+	ColorToggle:                "toggle",
+	ColorToggleOn:              "toggle_on",
+	ColorToggleKnob:            "toggle_knob",
+	ColorToggleKnobHovered:     "toggle_knob_hovered",
+	ColorSliderTrack:           "slider_track",
+	ColorSliderFill:            "slider_fill",
+	ColorSliderHandle:          "slider_handle",
+	ColorSliderHandleActive:    "slider_handle_active",
+	ColorInput:                 "input",
+	ColorInputFocused:          "input_focused",
+	ColorSelection:             "selection",
+	ColorCaret:                 "caret",
+	ColorPanel:                 "panel",
+	ColorPanelTitle:            "panel_title",
+	ColorPanelTitleHovered:     "panel_title_hovered",
+	ColorPanelTitleText:        "panel_title_text",
+	ColorPanelIndicator:        "panel_indicator",
+	ColorPanelIndicatorHovered: "panel_indicator_hovered",
+	ColorScrollbar:             "scrollbar",
+	ColorScrollbarThumb:        "scrollbar_thumb",
+	ColorScrollbarThumbHovered: "scrollbar_thumb_hovered",
+	ColorItemHovered:           "item_hovered",
+	ColorPopup:                 "popup",
+	ColorHeader:                "header",
+	ColorHeaderHovered:         "header_hovered",
+	ColorTab:                   "tab",
+	ColorTabHovered:            "tab_hovered",
+	ColorTabActive:             "tab_active",
+	ColorBorder:                "border",
+	ColorModalDim:              "modal_dim",
+	ColorDockPreview:           "dock_preview",
+}
+
+// String returns the name of the color used in style JSON.
+func (sc StyleColor) String() string {
+	if sc < 0 || sc >= ColorCount {
+		return "StyleColor(" + strconv.Itoa(int(sc)) + ")"
+	}
+	return styleColorNames[sc]
+}
+
+// Style holds the colors, metrics and font widgets are drawn with.
+// Styles are plain values: copy a preset, change what you need and pass it to
+// Context.SetStyle, or override single colors for a few widgets with PushStyleColor.
+//
+// Styles can be stored as JSON, with colors written as "#rrggbb" or "#rrggbbaa":
+//
+//	{"font_size": 16, "colors": {"button": "#3c5a8c", "text": "#ffffff"}}
+//
+// Fields missing from the JSON keep the value of the style it is decoded into.
+type Style struct {
+	Colors [ColorCount]color.RGBA
+
+	// Font is the font widgets are drawn with; nil uses hlg's default font.
+	// SetStyle passes the font to the Context's Renderer; DefaultRenderer makes it hlg's
+	// default font. PushStyle does not switch fonts within a frame.
+	Font     *hlg.Font
+	FontSize float32
+
+	FrameRounding  int // corner radius of buttons, inputs and sliders
+	PanelRounding  int // corner radius of panels
+	TitleBarHeight int // height of the panel title bar
+	ItemHeight     int // natural height of a line of widgets in a layout
+	Padding        int // space between the edge of panels and layouts and their content
+	Spacing        int // space between widgets in a layout
+	ScrollbarSize  int // width of vertical and height of horizontal scrollbars
+	IndentSpacing  int // indentation of the children of a tree node
+}
+
+//go:embed themes/*.json
+var themeFiles embed.FS
+
+var (
+	darkStyle         = mustLoadTheme("themes/dark.json")
+	lightStyle        = mustLoadTheme("themes/light.json")
+	highContrastStyle = mustLoadTheme("themes/high_contrast.json")
+)
+
+func mustLoadTheme(name string) Style {
+	data, err := themeFiles.ReadFile(name)
+	if err != nil {
+		panic(fmt.Sprintf("gui: missing theme %s: %v", name, err))
+	}
+	var s Style
+	if err := json.Unmarshal(data, &s); err != nil {
+		panic(fmt.Sprintf("gui: invalid theme %s: %v", name, err))
+	}
+	return s
+}
+
+// DarkStyle returns the default dark style.
+func DarkStyle() Style {
+	return darkStyle
+}
+
+// LightStyle returns a light style.
+func LightStyle() Style {
+	return lightStyle
+}
+
+// HighContrastStyle returns a style with high contrast colors and larger text,
+// for players who need stronger visual cues.
+func HighContrastStyle() Style {
+	return highContrastStyle
+}
+
+// LoadStyle decodes a style from JSON. Fields missing from the JSON keep the values
+// of DarkStyle.
+func LoadStyle(data []byte) (Style, error) {
+	s := DarkStyle()
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Style{}, fmt.Errorf("failed to parse style: %w", err)
+	}
+	return s, nil
+}
+
+// LoadStyleFile reads a style from a JSON file. See LoadStyle.
+func LoadStyleFile(path string) (Style, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Style{}, fmt.Errorf("failed to read style file: %w", err)
+	}
+	return LoadStyle(data)
+}
+
+// styleJSON is the JSON form of Style. Pointers tell missing fields from zero values.
+type styleJSON struct {
+	FontSize       *float32          `json:"font_size,omitempty"`
+	FrameRounding  *int              `json:"frame_rounding,omitempty"`
+	PanelRounding  *int              `json:"panel_rounding,omitempty"`
+	TitleBarHeight *int              `json:"title_bar_height,omitempty"`
+	ItemHeight     *int              `json:"item_height,omitempty"`
+	Padding        *int              `json:"padding,omitempty"`
+	Spacing        *int              `json:"spacing,omitempty"`
+	ScrollbarSize  *int              `json:"scrollbar_size,omitempty"`
+	IndentSpacing  *int              `json:"indent_spacing,omitempty"`
+	Colors         map[string]string `json:"colors,omitempty"`
+}
+
+// MarshalJSON encodes the style's colors and metrics. The font is not encoded.
+func (s Style) MarshalJSON() ([]byte, error) {
+	j := styleJSON{
+		FontSize:       &s.FontSize,
+		FrameRounding:  &s.FrameRounding,
+		PanelRounding:  &s.PanelRounding,
+		TitleBarHeight: &s.TitleBarHeight,
+		ItemHeight:     &s.ItemHeight,
+		Padding:        &s.Padding,
+		Spacing:        &s.Spacing,
+		ScrollbarSize:  &s.ScrollbarSize,
+		IndentSpacing:  &s.IndentSpacing,
+		Colors:         make(map[string]string, ColorCount),
+	}
+	for i, c := range s.Colors {
+		j.Colors[styleColorNames[i]] = formatHexColor(c)
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes the fields present in the JSON into the style.
+func (s *Style) UnmarshalJSON(data []byte) error {
+	var j styleJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+	setIfPresent(&s.FontSize, j.FontSize)
+	setIfPresent(&s.FrameRounding, j.FrameRounding)
+	setIfPresent(&s.PanelRounding, j.PanelRounding)
+	setIfPresent(&s.TitleBarHeight, j.TitleBarHeight)
+	setIfPresent(&s.ItemHeight, j.ItemHeight)
+	setIfPresent(&s.Padding, j.Padding)
+	setIfPresent(&s.Spacing, j.Spacing)
+	setIfPresent(&s.ScrollbarSize, j.ScrollbarSize)
+	setIfPresent(&s.IndentSpacing, j.IndentSpacing)
+
+	for name, value := range j.Colors {
+		idx := -1
+		for i, n := range styleColorNames {
+			if n == name {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return fmt.Errorf("unknown style color %q", name)
+		}
+		c, err := parseHexColor(value)
+		if err != nil {
+			return fmt.Errorf("style color %q: %w", name, err)
+		}
+		s.Colors[idx] = c
+	}
+	return nil
+}
+
+func setIfPresent[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// parseHexColor parses "#rrggbb" or "#rrggbbaa".
+func parseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, want #rrggbb or #rrggbbaa", s)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q, want #rrggbb or #rrggbbaa", s)
+	}
+	return color.RGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+func formatHexColor(c color.RGBA) string {
+	if c.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
+
+// SetStyle replaces the style widgets are drawn with and drops any pushed overrides.
+func (c *Context) SetStyle(s Style) {
+	c.style = s
+	c.styleStack = c.styleStack[:0]
+	c.applyStyleFont()
+}
+
+// GetStyle returns the style widgets are currently drawn with, including pushed overrides.
+func (c *Context) GetStyle() Style {
+	return c.style
+}
+
+// PushStyle replaces the whole style, except the font, until the matching PopStyle.
+func (c *Context) PushStyle(s Style) {
+	c.styleStack = append(c.styleStack, c.style)
+	c.style = s
+}
+
+// PushStyleColor overrides one color until the matching PopStyle.
+//
+//	ctx.PushStyleColor(gui.ColorButton, colornames.Darkred)
+//	ctx.AddButton("Delete")
+//	ctx.PopStyle()
+func (c *Context) PushStyleColor(idx StyleColor, col color.Color) {
+	c.styleStack = append(c.styleStack, c.style)
+	if idx >= 0 && idx < ColorCount {
+		c.style.Colors[idx] = color.RGBAModel.Convert(col).(color.RGBA)
+	}
+}
+
+// PopStyle undoes the last PushStyle or PushStyleColor.
+func (c *Context) PopStyle() {
+	if len(c.styleStack) == 0 {
+		return
+	}
+	c.style = c.styleStack[len(c.styleStack)-1]
+	c.styleStack = c.styleStack[:len(c.styleStack)-1]
+}
+
+// applyStyleFont makes the style's font the one text is drawn and measured with.
+func (c *Context) applyStyleFont() {
+	if c.style.Font != nil {
+		c.renderer.SetFont(c.style.Font)
+	}
+}
+
+// color returns a color of the current style.
+func (c *Context) color(idx StyleColor) color.RGBA {
+	return c.style.Colors[idx]
+}
@@ -0,0 +1,355 @@
+package gui
+
+import (
+	"strconv"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+const (
+	minColumnWidth   = 24 // narrowest a column can be resized to
+	resizeHandleSize = 6  // width of the grab area on the right edge of a column header
+	cellPadding      = 6  // space between the sides of a table cell and its content
+)
+
+// TableColumn describes a column of a table.
+type TableColumn struct {
+	Header   string
+	Width    int  // initial width in pixels; 0 shares the width left by the other columns
+	Sortable bool // clicking the header sorts the table by this column
+}
+
+// table is a table opened by BeginTable.
+type table struct {
+	id                         ID
+	state                      *TableState
+	viewX, viewY, viewW, viewH int // the visible part of the body
+	rowsX, rowsY               int // top left of the first row, moved by scrolling
+	rowH                       int
+	row, column                int // current row and column, -1 before the first
+	cellOpen                   bool
+	changed                    bool // the selected row changed
+}
+
+// BeginTable opens a table on the given rectangle of the screen: a header with a cell
+// for each column, above a scrolling body of rows. Add rows with TableNextRow and fill
+// their cells with TableNextColumn, then close the table with EndTable:
+//
+//	if ctx.BeginTable("entities", &tableState, columns, x, y, w, h) {
+//	    sortEntities(entities, tableState.SortColumn, tableState.SortDescending)
+//	}
+//	for _, e := range entities {
+//	    if !ctx.TableNextRow() {
+//	        continue // scrolled out of view
+//	    }
+//	    ctx.TableNextColumn()
+//	    ctx.AddLabel(e.Name)
+//	    ctx.TableNextColumn()
+//	    ctx.AddSlider(e.Name+"_speed", &e.Speed, 0, 10)
+//	}
+//	ctx.EndTable()
+//
+// Each cell is a column layout one item high, clipped to the cell, so the Add* methods
+// place widgets in it. Dragging the right edge of a header resizes its column, and
+// clicking a sortable header sorts by that column, toggling the direction when it is
+// already the sort column. The table only records the sort order; BeginTable returns
+// true on the first frame and whenever the order changed, so the caller can sort its rows.
+func (c *Context) BeginTable(label string, state *TableState, columns []TableColumn, x, y, w, h int) bool {
+	id := c.GetID(label + "_table")
+	c.registerFocusable(id)
+	focused := c.isFocused(id)
+
+	sortChanged := !state.initialized
+	if len(state.widths) != len(columns) {
+		state.widths = columnWidths(columns, w-4-c.style.ScrollbarSize)
+	}
+	state.initialized = true
+
+	rounding := c.style.FrameRounding
+	if focused {
+		c.roundedRectOutline(x, y, w, h, rounding, 2, c.color(ColorInput), c.color(ColorFocusRing))
+	} else {
+		c.roundedRect(x, y, w, h, rounding, c.color(ColorInput))
+	}
+	x, y, w, h = x+2, y+2, w-4, h-4
+
+	headerH := c.style.ItemHeight
+	if c.tableHeader(label, state, columns, x-state.scroll.ScrollX, y, x, w, headerH) {
+		sortChanged = true
+	}
+
+	style := c.style
+	style.Padding = 0
+	c.PushStyle(style)
+
+	c.BeginScroll(label+"_body", x, y+headerH, w, h-headerH, &state.scroll)
+	body := c.currentLayout()
+	region := body.scroll
+	c.tables = append(c.tables, &table{
+		id:     id,
+		state:  state,
+		viewX:  region.x,
+		viewY:  region.y,
+		viewW:  region.viewW,
+		viewH:  region.viewH,
+		rowsX:  body.x,
+		rowsY:  body.y,
+		rowH:   c.style.ItemHeight,
+		row:    -1,
+		column: -1,
+	})
+
+	return sortChanged
+}
+
+// tableHeader draws the column headers starting at (x, y), clipped to the width w from
+// clipX, and handles resizing and sorting. Returns true if the sort order changed.
+func (c *Context) tableHeader(label string, state *TableState, columns []TableColumn, x, y, clipX, w, h int) bool {
+	mx, my := c.input.MousePosition()
+	blocked := c.isInputBlocked(mx, my) || !pointInRect(mx, my, clipX, y, w, h)
+	fontSize := c.style.FontSize
+	sortChanged := false
+
+	c.pushClip(clipX, y, w, h)
+	c.filledRect(clipX, y, w, h, c.color(ColorHeader))
+
+	colX := x
+	for i, col := range columns {
+		colW := state.widths[i]
+		edge := colX + colW
+		resizeID := c.GetID(label + "_resize" + strconv.Itoa(i))
+		headerID := c.GetID(label + "_header" + strconv.Itoa(i))
+
+		onEdge := !blocked && pointInRect(mx, my, edge-resizeHandleSize/2, y, resizeHandleSize, h)
+		hovered := !blocked && !onEdge && pointInRect(mx, my, colX, y, colW, h)
+
+		if onEdge || c.isActive(resizeID) {
+			c.setCursor(input.CursorResizeH)
+		}
+		if onEdge {
+			c.setHot(resizeID)
+			if c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+				c.setActive(resizeID)
+				state.dragOff = edge - mx
+			}
+		}
+		if c.isActive(resizeID) {
+			if c.input.IsButtonPressed(input.MouseButtonLeft) {
+				colW = max(mx+state.dragOff-colX, minColumnWidth)
+				state.widths[i] = colW
+				edge = colX + colW
+			} else {
+				c.clearActive()
+			}
+		}
+
+		if hovered && col.Sortable {
+			c.setHot(headerID)
+			if c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+				c.setActive(headerID)
+			}
+		}
+		if c.isActive(headerID) && c.input.IsButtonJustReleased(input.MouseButtonLeft) {
+			if hovered {
+				if state.SortColumn == i {
+					state.SortDescending = !state.SortDescending
+				} else {
+					state.SortColumn, state.SortDescending = i, false
+				}
+				sortChanged = true
+			}
+			c.clearActive()
+		}
+
+		if col.Sortable && (hovered || c.isActive(headerID)) {
+			c.filledRect(colX, y, colW, h, c.color(ColorHeaderHovered))
+		}
+
+		textColor := c.color(ColorText)
+		c.text(col.Header, colX+cellPadding, y+(h-int(fontSize))/2, fontSize, textColor)
+		if col.Sortable && state.SortColumn == i {
+			arrowX, arrowY := edge-cellPadding-8, y+(h-6)/2
+			if state.SortDescending {
+				c.filledTriangle(arrowX, arrowY, arrowX+8, arrowY, arrowX+4, arrowY+6, textColor)
+			} else {
+				c.filledTriangle(arrowX+4, arrowY, arrowX+8, arrowY+6, arrowX, arrowY+6, textColor)
+			}
+		}
+
+		edgeColor := c.color(ColorBorder)
+		if onEdge || c.isActive(resizeID) {
+			edgeColor = c.color(ColorFocusRing)
+		}
+		c.filledRect(edge-1, y, 1, h, edgeColor)
+		colX = edge
+	}
+
+	c.popClip()
+	return sortChanged
+}
+
+// columnWidths returns the initial widths of the columns of a table whose body is w
+// pixels wide. Columns without a width share what the others leave.
+func columnWidths(columns []TableColumn, w int) []int {
+	widths := make([]int, len(columns))
+	shared := 0
+	for i, col := range columns {
+		if col.Width > 0 {
+			widths[i] = max(col.Width, minColumnWidth)
+			w -= widths[i]
+		} else {
+			shared++
+		}
+	}
+	for i, col := range columns {
+		if col.Width <= 0 {
+			widths[i] = max(w/shared, minColumnWidth)
+		}
+	}
+	return widths
+}
+
+// TableNextRow starts the next row of the table opened by BeginTable and returns true if
+// the row is in view. Rows out of view need not be filled, which keeps long tables cheap.
+// Clicking a row selects it.
+func (c *Context) TableNextRow() bool {
+	t := c.currentTable()
+	if t == nil {
+		return false
+	}
+	c.endTableCell(t)
+	t.row++
+	t.column = -1
+
+	rowY := t.rowsY + t.row*t.rowH
+	if rowY+t.rowH <= t.viewY || rowY >= t.viewY+t.viewH {
+		return false
+	}
+
+	rowW := max(t.state.totalWidth(), t.viewW)
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, t.rowsX, rowY, rowW, t.rowH)
+	if hovered {
+		c.setHot(t.id)
+		if c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+			c.setFocused(t.id)
+			t.changed = t.changed || t.state.Selected != t.row
+			t.state.Selected = t.row
+		}
+	}
+
+	switch {
+	case t.row == t.state.Selected:
+		c.filledRect(t.rowsX, rowY, rowW, t.rowH, c.color(ColorSelection))
+	case hovered:
+		c.filledRect(t.rowsX, rowY, rowW, t.rowH, c.color(ColorItemHovered))
+	}
+	return true
+}
+
+// TableNextColumn moves to the next cell of the current row, starting the first row if
+// none has been started. Widgets added with the Add* methods until the next cell, row
+// or EndTable go in the cell. Returns false past the last column.
+func (c *Context) TableNextColumn() bool {
+	t := c.currentTable()
+	if t == nil {
+		return false
+	}
+	if t.row < 0 {
+		c.TableNextRow()
+	}
+	c.endTableCell(t)
+	if t.column+1 >= len(t.state.widths) {
+		return false
+	}
+	t.column++
+
+	cellX := t.rowsX
+	for _, w := range t.state.widths[:t.column] {
+		cellX += w
+	}
+	cellW := t.state.widths[t.column]
+	rowY := t.rowsY + t.row*t.rowH
+
+	c.pushClip(cellX, rowY, cellW, t.rowH)
+	c.currentLayout()
+	c.pushLayout(&layout{direction: directionColumn, tableCell: true}, cellX+cellPadding, rowY, cellW-2*cellPadding, t.rowH, 0)
+	t.cellOpen = true
+	return true
+}
+
+// EndTable closes the table opened by BeginTable and returns true if the selected row
+// changed. While the table has focus, the up and down, page up/down and home/end keys
+// move the selection.
+func (c *Context) EndTable() bool {
+	t := c.currentTable()
+	if t == nil {
+		return false
+	}
+	c.endTableCell(t)
+	c.tables = c.tables[:len(c.tables)-1]
+
+	state := t.state
+	rows := t.row + 1
+	if c.isFocused(t.id) && rows > 0 {
+		pageRows := max(t.viewH/t.rowH, 1)
+		selected := state.Selected
+		switch {
+		case c.input.IsKeyJustPressed(input.KeyUp):
+			selected--
+		case c.input.IsKeyJustPressed(input.KeyDown):
+			selected++
+		case c.input.IsKeyJustPressed(input.KeyPageUp):
+			selected -= pageRows
+		case c.input.IsKeyJustPressed(input.KeyPageDown):
+			selected += pageRows
+		case c.input.IsKeyJustPressed(input.KeyHome):
+			selected = 0
+		case c.input.IsKeyJustPressed(input.KeyEnd):
+			selected = rows - 1
+		}
+		selected = min(max(selected, 0), rows-1)
+		if selected != state.Selected {
+			state.Selected = selected
+			t.changed = true
+			scrollIntoView(&state.scroll.ScrollY, selected*t.rowH, t.rowH, t.viewH)
+		}
+	}
+
+	// Column separators run down the body
+	totalW := state.totalWidth()
+	edgeX := t.rowsX
+	for _, w := range state.widths {
+		edgeX += w
+		c.filledRect(edgeX-1, t.viewY, 1, min(rows*t.rowH, t.viewH), c.color(ColorBorder))
+	}
+
+	c.nextRect(Fixed(totalW), Fixed(rows*t.rowH), totalW, rows*t.rowH)
+	c.EndScroll()
+	c.PopStyle()
+	return t.changed
+}
+
+// currentTable returns the innermost open table, or nil.
+func (c *Context) currentTable() *table {
+	if len(c.tables) == 0 {
+		return nil
+	}
+	return c.tables[len(c.tables)-1]
+}
+
+// endTableCell closes the open cell of a table, along with any layouts left open in it.
+func (c *Context) endTableCell(t *table) {
+	if !t.cellOpen {
+		return
+	}
+	for len(c.layouts) > 0 {
+		l := c.layouts[len(c.layouts)-1]
+		c.layouts = c.layouts[:len(c.layouts)-1]
+		if l.tableCell {
+			break
+		}
+	}
+	c.popClip()
+	t.cellOpen = false
+}
@@ -0,0 +1,115 @@
+package gui
+
+import "github.com/dfirebaugh/hlg/pkg/input"
+
+// tabBar is a tab bar opened by TabBar.
+type tabBar struct {
+	id       ID
+	selected *int
+	x, y, w  int
+	cursorX  int // where the next tab goes
+	count    int // tabs added so far
+	clicked  int // tab pressed this frame, or -1
+}
+
+// TabBar opens a bar of tabs, one item high, along the top of the given width.
+// Add the tabs with TabItem, which returns true for the selected tab so its content can
+// be drawn below the bar, then close the bar with EndTabBar:
+//
+//	ctx.TabBar("inspector", &tab, x, y, w)
+//	if ctx.TabItem("Transform") {
+//	    drawTransform()
+//	}
+//	if ctx.TabItem("Physics") {
+//	    drawPhysics()
+//	}
+//	ctx.EndTabBar()
+//
+// *selected is the index of the selected tab. Clicking a tab selects it, and while the
+// bar has focus the left and right keys select the neighbouring tab. The selection
+// changes at EndTabBar, so only one tab's content is drawn in a frame.
+func (c *Context) TabBar(label string, selected *int, x, y, w int) {
+	id := c.GetID(label + "_tabbar")
+	c.registerFocusable(id)
+	c.tabBars = append(c.tabBars, &tabBar{id: id, selected: selected, x: x, y: y, w: w, cursorX: x, clicked: -1})
+}
+
+// TabItem adds a tab to the bar opened by TabBar and returns true if it is the selected tab.
+func (c *Context) TabItem(label string) bool {
+	if len(c.tabBars) == 0 {
+		return false
+	}
+	t := c.tabBars[len(c.tabBars)-1]
+	index := t.count
+	t.count++
+
+	fontSize := c.style.FontSize
+	x, y := t.cursorX, t.y
+	w, h := int(c.renderer.MeasureText(label, fontSize))+24, c.style.ItemHeight
+	t.cursorX += w + 2
+
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, x, y, w, h) && pointInRect(mx, my, t.x, t.y, t.w, h)
+	active := index == *t.selected
+
+	if hovered {
+		c.setHot(t.id)
+		if c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+			t.clicked = index
+			c.setFocused(t.id)
+		}
+	}
+
+	bgColor := c.color(ColorTab)
+	switch {
+	case active:
+		bgColor = c.color(ColorTabActive)
+	case hovered:
+		bgColor = c.color(ColorTabHovered)
+	}
+
+	// Tabs that do not fit are cut off at the end of the bar
+	c.pushClip(t.x, t.y, t.w, h)
+	rounding := c.style.FrameRounding
+	if active && c.isFocused(t.id) {
+		c.roundedRectOutline(x, y, w, h+rounding, rounding, 1, bgColor, c.color(ColorFocusRing))
+	} else {
+		// Square off the bottom corners, which join the line under the bar
+		c.roundedRect(x, y, w, h, rounding, bgColor)
+		c.filledRect(x, y+h-rounding, w, rounding, bgColor)
+	}
+	c.text(label, x+12, y+(h-int(fontSize))/2, fontSize, c.color(ColorText))
+	c.popClip()
+
+	return active
+}
+
+// EndTabBar closes the bar opened by TabBar and returns true if the selected tab changed.
+func (c *Context) EndTabBar() bool {
+	if len(c.tabBars) == 0 {
+		return false
+	}
+	t := c.tabBars[len(c.tabBars)-1]
+	c.tabBars = c.tabBars[:len(c.tabBars)-1]
+
+	selected := t.clicked
+	if selected < 0 {
+		selected = *t.selected
+		if c.isFocused(t.id) {
+			if c.input.IsKeyJustPressed(input.KeyLeft) {
+				selected--
+			}
+			if c.input.IsKeyJustPressed(input.KeyRight) {
+				selected++
+			}
+		}
+	}
+	selected = min(max(selected, 0), max(t.count-1, 0))
+
+	h := c.style.ItemHeight
+	c.filledRect(t.x, t.y+h-2, t.w, 2, c.color(ColorTabActive))
+
+	changed := selected != *t.selected
+	*t.selected = selected
+	return changed
+}
@@ -0,0 +1,340 @@
+package gui
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// textAreaPadding is the space between the edge of a text area and its text.
+const textAreaPadding = 4
+
+// TextArea renders a multiline text editor and returns true if the text changed.
+// The text is stored in the provided pointer and the state holds the caret, selection,
+// scroll position and undo history.
+//
+// Editing keys while the text area has focus:
+//   - arrows, home/end and page up/down move the caret; shift selects as it moves
+//   - ctrl+left/right jump by word, ctrl+home/end go to the start or end of the text
+//   - ctrl+backspace/delete delete a word
+//   - ctrl+a selects all; ctrl+c, ctrl+x and ctrl+v copy, cut and paste through the
+//     Context's Clipboard
+//   - ctrl+z undoes, ctrl+y or ctrl+shift+z redoes
+//
+// Tab still moves focus to the next widget. The label is used for widget identification
+// (not displayed).
+func (c *Context) TextArea(label string, text *string, state *TextAreaState, x, y, w, h int) bool {
+	id := c.GetID(label + "_textarea")
+	c.registerFocusable(id)
+	focused := c.isFocused(id)
+
+	fontSize := c.style.FontSize
+	lineH := c.textLineHeight()
+	sb := c.style.ScrollbarSize
+
+	ed := textEditor{text: text, cursor: state.CursorPos, anchor: state.anchor}
+	ed.clamp()
+
+	changed, moved := false, false
+	if focused {
+		changed, moved = c.handleTextAreaKeys(&ed, state, lineH, h-4-2*textAreaPadding)
+	}
+
+	lines := strings.Split(*text, "\n")
+	textW := 0
+	for _, line := range lines {
+		textW = max(textW, int(c.renderer.MeasureText(line, fontSize)))
+	}
+
+	// Measure the content now rather than at EndScroll so new lines can be scrolled to
+	scroll := &state.scroll
+	scroll.contentW = textW + 4 + 2*textAreaPadding
+	scroll.contentH = len(lines)*lineH + 2*textAreaPadding
+	if moved {
+		viewW, viewH := w-4, h-4
+		if scroll.contentH > viewH {
+			viewW -= sb
+		}
+		if scroll.contentW > viewW {
+			viewH -= sb
+		}
+		caretLine := strings.Count((*text)[:ed.cursor], "\n")
+		start := lineStart(*text, ed.cursor)
+		caretX, _, _, _ := c.renderer.TextCaretRect((*text)[start:lineEnd(*text, ed.cursor)], fontSize, ed.cursor-start)
+		scrollIntoView(&scroll.ScrollY, textAreaPadding+caretLine*lineH, lineH, viewH)
+		scrollIntoView(&scroll.ScrollX, textAreaPadding+int(caretX), 2, viewW)
+	}
+
+	bgColor := c.color(ColorInput)
+	if focused {
+		bgColor = c.color(ColorInputFocused)
+	}
+	rounding := c.style.FrameRounding
+	if focused {
+		c.roundedRectOutline(x, y, w, h, rounding, 2, bgColor, c.color(ColorFocusRing))
+	} else {
+		c.roundedRect(x, y, w, h, rounding, bgColor)
+	}
+
+	style := c.style
+	style.Padding = textAreaPadding
+	c.PushStyle(style)
+	defer c.PopStyle()
+
+	c.BeginScroll(label+"_scroll", x+2, y+2, w-4, h-4, scroll)
+	textX, textY, _, _ := c.nextRect(Fill(), Fixed(len(lines)*lineH), textW+4, len(lines)*lineH)
+
+	// Mouse selection
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, x+2, y+2, w-4, h-4)
+	if hovered {
+		c.setHot(id)
+		c.setCursor(input.CursorIBeam)
+		if c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+			c.setActive(id)
+			c.setFocused(id)
+			focused = true
+			ed.moveTo(c.textPosAt(lines, mx-textX, my-textY, lineH, fontSize), c.shiftHeld)
+			state.hasPreferredX = false
+			state.history.breakGroup()
+		}
+	}
+	if c.isActive(id) {
+		if c.input.IsButtonPressed(input.MouseButtonLeft) {
+			ed.moveTo(c.textPosAt(lines, mx-textX, my-textY, lineH, fontSize), true)
+		} else {
+			c.clearActive()
+		}
+	}
+
+	// Only the lines in view are drawn
+	first := max((scroll.ScrollY-textAreaPadding)/lineH, 0)
+	last := min((scroll.ScrollY+h)/lineH+1, len(lines))
+	selStart, selEnd := ed.selection()
+	showCaret := focused && ((time.Since(c.frameTime)/blinkInterval)%2 == 0 || c.isActive(id))
+
+	start := 0
+	for _, line := range lines[:first] {
+		start += len(line) + 1
+	}
+	for i := first; i < last; i++ {
+		line := lines[i]
+		end := start + len(line)
+		lineY := textY + i*lineH
+
+		if selStart < selEnd && selStart <= end && selEnd > start {
+			x0, _, _, _ := c.renderer.TextCaretRect(line, fontSize, max(selStart, start)-start)
+			x1, _, _, _ := c.renderer.TextCaretRect(line, fontSize, min(selEnd, end)-start)
+			if selEnd > end {
+				x1 += 4 // show that the newline is selected
+			}
+			c.filledRect(textX+int(x0), lineY, int(x1-x0), lineH, c.color(ColorSelection))
+		}
+
+		c.text(line, textX, lineY+(lineH-int(fontSize))/2, fontSize, c.color(ColorText))
+
+		if showCaret && ed.cursor >= start && ed.cursor <= end {
+			caretX, _, _, _ := c.renderer.TextCaretRect(line, fontSize, ed.cursor-start)
+			c.filledRect(textX+int(caretX), lineY+2, 2, lineH-4, c.color(ColorCaret))
+		}
+		start = end + 1
+	}
+
+	c.EndScroll()
+
+	state.CursorPos, state.anchor = ed.cursor, ed.anchor
+	return changed
+}
+
+// textLineHeight returns the distance between lines in a text area.
+func (c *Context) textLineHeight() int {
+	return int(c.style.FontSize) + 6
+}
+
+// handleTextAreaKeys processes keyboard input for a text area. viewH is the height of
+// the visible text, used to move by pages. Returns whether the text changed and whether
+// the caret moved.
+func (c *Context) handleTextAreaKeys(ed *textEditor, state *TextAreaState, lineH, viewH int) (changed, moved bool) {
+	pressed, repeating := c.input.IsKeyJustPressed, c.keyRepeating
+	shift, ctrl := c.shiftHeld, c.ctrlHeld
+	startCursor, startText := ed.cursor, *ed.text
+
+	edit := func(kind editKind, replacement string) {
+		if ed.hasSelection() && kind == editTyping {
+			kind = editOther // replacing a selection starts a new undo step
+		}
+		state.history.record(ed.snapshot(), kind, c.frameTime)
+		ed.replaceSelection(replacement)
+	}
+	move := func(pos int) {
+		ed.moveTo(pos, shift)
+		state.history.breakGroup()
+		state.hasPreferredX = false
+	}
+
+	for _, ch := range c.input.GetCharInput() {
+		if unicode.IsPrint(ch) {
+			edit(editTyping, string(ch))
+		}
+	}
+
+	if pressed(input.KeyEnter) {
+		edit(editOther, "\n")
+	}
+
+	if repeating(input.KeyBackspace) {
+		if !ed.hasSelection() && ed.cursor > 0 {
+			if ctrl {
+				ed.anchor = prevWord(*ed.text, ed.cursor)
+			} else {
+				ed.anchor = prevRune(*ed.text, ed.cursor)
+			}
+		}
+		if ed.hasSelection() {
+			edit(editDeleting, "")
+		}
+	}
+
+	if repeating(input.KeyDelete) {
+		if !ed.hasSelection() && ed.cursor < len(*ed.text) {
+			if ctrl {
+				ed.anchor = nextWord(*ed.text, ed.cursor)
+			} else {
+				ed.anchor = nextRune(*ed.text, ed.cursor)
+			}
+		}
+		if ed.hasSelection() {
+			edit(editDeleting, "")
+		}
+	}
+
+	if ctrl {
+		switch {
+		case pressed(input.KeyA):
+			ed.anchor, ed.cursor = 0, len(*ed.text)
+		case pressed(input.KeyC) && ed.hasSelection():
+			c.clipboard.SetClipboardText(ed.selectedText())
+		case pressed(input.KeyX) && ed.hasSelection():
+			c.clipboard.SetClipboardText(ed.selectedText())
+			edit(editOther, "")
+		case pressed(input.KeyV):
+			if paste := strings.ReplaceAll(c.clipboard.GetClipboardText(), "\r\n", "\n"); paste != "" {
+				edit(editOther, paste)
+			}
+		case pressed(input.KeyZ) && !shift:
+			if snap, ok := state.history.undoStep(ed.snapshot()); ok {
+				ed.restore(snap)
+			}
+		case pressed(input.KeyY), pressed(input.KeyZ) && shift:
+			if snap, ok := state.history.redoStep(ed.snapshot()); ok {
+				ed.restore(snap)
+			}
+		}
+	}
+
+	text := *ed.text
+	if repeating(input.KeyLeft) {
+		start, _ := ed.selection()
+		switch {
+		case ed.hasSelection() && !shift:
+			move(start)
+		case ctrl:
+			move(prevWord(text, ed.cursor))
+		case ed.cursor > 0:
+			move(prevRune(text, ed.cursor))
+		}
+	}
+
+	if repeating(input.KeyRight) {
+		_, end := ed.selection()
+		switch {
+		case ed.hasSelection() && !shift:
+			move(end)
+		case ctrl:
+			move(nextWord(text, ed.cursor))
+		case ed.cursor < len(text):
+			move(nextRune(text, ed.cursor))
+		}
+	}
+
+	if pressed(input.KeyHome) {
+		if ctrl {
+			move(0)
+		} else {
+			move(lineStart(text, ed.cursor))
+		}
+	}
+
+	if pressed(input.KeyEnd) {
+		if ctrl {
+			move(len(text))
+		} else {
+			move(lineEnd(text, ed.cursor))
+		}
+	}
+
+	pageLines := max(viewH/lineH-1, 1)
+	switch {
+	case repeating(input.KeyUp):
+		c.moveTextLines(ed, state, -1)
+	case repeating(input.KeyDown):
+		c.moveTextLines(ed, state, 1)
+	case pressed(input.KeyPageUp):
+		c.moveTextLines(ed, state, -pageLines)
+	case pressed(input.KeyPageDown):
+		c.moveTextLines(ed, state, pageLines)
+	}
+
+	changed = *ed.text != startText
+	if changed {
+		state.hasPreferredX = false
+	}
+	return changed, changed || ed.cursor != startCursor
+}
+
+// moveTextLines moves the caret up (negative) or down by lines, keeping it at the same
+// horizontal position. Moving past the first or last line goes to the start or end of
+// the text.
+func (c *Context) moveTextLines(ed *textEditor, state *TextAreaState, lines int) {
+	text := *ed.text
+	fontSize := c.style.FontSize
+	start := lineStart(text, ed.cursor)
+	if !state.hasPreferredX {
+		state.preferredX, _, _, _ = c.renderer.TextCaretRect(text[start:lineEnd(text, start)], fontSize, ed.cursor-start)
+		state.hasPreferredX = true
+	}
+
+	target := start
+	for ; lines < 0; lines++ {
+		if target == 0 {
+			ed.moveTo(0, c.shiftHeld)
+			return
+		}
+		target = lineStart(text, target-1)
+	}
+	for ; lines > 0; lines-- {
+		end := lineEnd(text, target)
+		if end == len(text) {
+			ed.moveTo(len(text), c.shiftHeld)
+			return
+		}
+		target = end + 1
+	}
+
+	line := text[target:lineEnd(text, target)]
+	ed.moveTo(target+c.renderer.TextIndexAtPoint(line, fontSize, state.preferredX, 0), c.shiftHeld)
+	state.history.breakGroup()
+}
+
+// textPosAt returns the position in the text split into lines closest to (x, y),
+// relative to where the text is drawn with lines lineH pixels apart.
+func (c *Context) textPosAt(lines []string, x, y, lineH int, fontSize float32) int {
+	lineIndex := min(max(y/lineH, 0), len(lines)-1)
+	start := 0
+	for _, line := range lines[:lineIndex] {
+		start += len(line) + 1
+	}
+	return start + c.renderer.TextIndexAtPoint(lines[lineIndex], fontSize, float32(x), 0)
+}
@@ -0,0 +1,209 @@
+package gui
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxUndoSteps  = 200         // oldest undo steps are dropped past this
+	undoGroupTime = time.Second // typing within this long of the last edit joins its undo step
+)
+
+// textEditor applies editing commands to a string with a caret and a selection.
+// Positions are byte offsets on UTF-8 character boundaries; the selection runs between
+// anchor and cursor and is empty when they are equal.
+type textEditor struct {
+	text           *string
+	cursor, anchor int
+}
+
+// clamp moves the caret and anchor onto character boundaries of the text, which may
+// have been changed by the caller since the last frame.
+func (e *textEditor) clamp() {
+	e.cursor = clampToRune(*e.text, e.cursor)
+	e.anchor = clampToRune(*e.text, e.anchor)
+}
+
+func (e *textEditor) hasSelection() bool {
+	return e.cursor != e.anchor
+}
+
+// selection returns the start and end of the selection.
+func (e *textEditor) selection() (start, end int) {
+	return min(e.cursor, e.anchor), max(e.cursor, e.anchor)
+}
+
+func (e *textEditor) selectedText() string {
+	start, end := e.selection()
+	return (*e.text)[start:end]
+}
+
+// replaceSelection replaces the selected text with s, or inserts s at the caret.
+func (e *textEditor) replaceSelection(s string) {
+	start, end := e.selection()
+	*e.text = (*e.text)[:start] + s + (*e.text)[end:]
+	e.cursor = start + len(s)
+	e.anchor = e.cursor
+}
+
+// moveTo moves the caret to pos, extending the selection when extend is true.
+func (e *textEditor) moveTo(pos int, extend bool) {
+	e.cursor = pos
+	if !extend {
+		e.anchor = pos
+	}
+}
+
+func (e *textEditor) snapshot() textSnapshot {
+	return textSnapshot{text: *e.text, cursor: e.cursor, anchor: e.anchor}
+}
+
+func (e *textEditor) restore(s textSnapshot) {
+	*e.text = s.text
+	e.cursor, e.anchor = s.cursor, s.anchor
+}
+
+// editKind tells which edits may share an undo step.
+type editKind int
+
+const (
+	editNone editKind = iota
+	editTyping
+	editDeleting
+	editOther // pastes, cuts and new lines always get their own undo step
+)
+
+// textSnapshot is the text and selection before an edit.
+type textSnapshot struct {
+	text           string
+	cursor, anchor int
+}
+
+// textHistory is the undo and redo history of a text widget.
+type textHistory struct {
+	undo, redo []textSnapshot
+	lastKind   editKind
+	lastTime   time.Time
+}
+
+// record saves the text before an edit. Typing or deleting soon after an edit of the
+// same kind extends that edit's undo step instead of starting a new one.
+func (h *textHistory) record(before textSnapshot, kind editKind, now time.Time) {
+	grouped := kind != editOther && kind == h.lastKind && now.Sub(h.lastTime) < undoGroupTime
+	h.lastKind, h.lastTime = kind, now
+	if grouped {
+		return
+	}
+	h.undo = append(h.undo, before)
+	if len(h.undo) > maxUndoSteps {
+		h.undo = append(h.undo[:0], h.undo[1:]...)
+	}
+	h.redo = h.redo[:0]
+}
+
+// breakGroup makes the next edit start a new undo step, e.g. after the caret moved.
+func (h *textHistory) breakGroup() {
+	h.lastKind = editNone
+}
+
+// undoStep returns the text before the last edit and saves current for redo.
+func (h *textHistory) undoStep(current textSnapshot) (textSnapshot, bool) {
+	return h.step(&h.undo, &h.redo, current)
+}
+
+// redoStep returns the text after the last undone edit and saves current for undo.
+func (h *textHistory) redoStep(current textSnapshot) (textSnapshot, bool) {
+	return h.step(&h.redo, &h.undo, current)
+}
+
+func (h *textHistory) step(from, to *[]textSnapshot, current textSnapshot) (textSnapshot, bool) {
+	if len(*from) == 0 {
+		return current, false
+	}
+	snap := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, current)
+	h.lastKind = editNone
+	return snap, true
+}
+
+// clampToRune clamps pos to the text and moves it back onto the start of a character.
+func clampToRune(text string, pos int) int {
+	pos = min(max(pos, 0), len(text))
+	for pos > 0 && pos < len(text) && !utf8.RuneStart(text[pos]) {
+		pos--
+	}
+	return pos
+}
+
+// prevRune returns the position of the character before pos.
+func prevRune(text string, pos int) int {
+	_, size := utf8.DecodeLastRuneInString(text[:pos])
+	return pos - size
+}
+
+// nextRune returns the position of the character after pos.
+func nextRune(text string, pos int) int {
+	_, size := utf8.DecodeRuneInString(text[pos:])
+	return pos + size
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// prevWord returns the start of the word before pos, skipping any spaces and
+// punctuation in between.
+func prevWord(text string, pos int) int {
+	for pos > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:pos])
+		if isWordRune(r) {
+			break
+		}
+		pos -= size
+	}
+	for pos > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:pos])
+		if !isWordRune(r) {
+			break
+		}
+		pos -= size
+	}
+	return pos
+}
+
+// nextWord returns the end of the word after pos, skipping any spaces and punctuation
+// in between.
+func nextWord(text string, pos int) int {
+	for pos < len(text) {
+		r, size := utf8.DecodeRuneInString(text[pos:])
+		if isWordRune(r) {
+			break
+		}
+		pos += size
+	}
+	for pos < len(text) {
+		r, size := utf8.DecodeRuneInString(text[pos:])
+		if !isWordRune(r) {
+			break
+		}
+		pos += size
+	}
+	return pos
+}
+
+// lineStart returns the start of the line containing pos.
+func lineStart(text string, pos int) int {
+	return strings.LastIndexByte(text[:pos], '\n') + 1
+}
+
+// lineEnd returns the end of the line containing pos, before its newline.
+func lineEnd(text string, pos int) int {
+	if i := strings.IndexByte(text[pos:], '\n'); i >= 0 {
+		return pos + i
+	}
+	return len(text)
+}
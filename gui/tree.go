@@ -0,0 +1,114 @@
+package gui
+
+import "github.com/dfirebaugh/hlg/pkg/input"
+
+// TreeNode renders the header of a collapsible tree node: an arrow showing whether the
+// node is open, followed by its label. Clicking the header toggles *open; while the
+// node has focus, the right and left keys open and close it and enter or space toggle it.
+// Returns true if the node was opened or closed.
+//
+// The children are drawn by the caller when *open is true. AddTreeNode indents them
+// in a layout.
+func (c *Context) TreeNode(label string, open *bool, x, y, w, h int) bool {
+	id := c.GetID(label + "_tree")
+	c.registerFocusable(id)
+
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, x, y, w, h)
+	focused := c.isFocused(id)
+
+	if hovered {
+		c.setHot(id)
+	}
+
+	wasOpen := *open
+	if hovered && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+		c.setActive(id)
+		c.setFocused(id)
+		focused = true
+	}
+	if c.isActive(id) && c.input.IsButtonJustReleased(input.MouseButtonLeft) {
+		if hovered {
+			*open = !*open
+		}
+		c.clearActive()
+	}
+
+	if focused {
+		switch {
+		case c.input.IsKeyJustPressed(input.KeyRight):
+			*open = true
+		case c.input.IsKeyJustPressed(input.KeyLeft):
+			*open = false
+		case c.input.IsKeyJustPressed(input.KeyEnter) || c.input.IsKeyJustPressed(input.KeySpace):
+			*open = !*open
+		}
+	}
+
+	if hovered || c.isActive(id) {
+		c.filledRect(x, y, w, h, c.color(ColorItemHovered))
+	}
+	if focused {
+		focusColor := c.color(ColorFocusRing)
+		c.roundedRectOutline(x, y, w, h, c.style.FrameRounding, 1, c.color(ColorItemHovered), focusColor)
+	}
+
+	textColor := c.color(ColorText)
+	arrowX, arrowY := x+4, y+(h-8)/2
+	if *open {
+		c.filledTriangle(arrowX, arrowY+1, arrowX+8, arrowY+1, arrowX+4, arrowY+7, textColor)
+	} else {
+		c.filledTriangle(arrowX+1, arrowY, arrowX+7, arrowY+4, arrowX+1, arrowY+8, textColor)
+	}
+
+	fontSize := c.style.FontSize
+	c.text(label, x+18, y+(h-int(fontSize))/2, fontSize, textColor)
+
+	return *open != wasOpen
+}
+
+// Selectable renders a row of text that is highlighted while selected or hovered, such
+// as a leaf of a tree, and returns true if it was clicked or activated with enter or space.
+// The caller owns the selection and passes whether this row is selected.
+func (c *Context) Selectable(label string, selected bool, x, y, w, h int) bool {
+	id := c.GetID(label + "_selectable")
+	c.registerFocusable(id)
+
+	mx, my := c.input.MousePosition()
+	hovered := !c.isInputBlocked(mx, my) && pointInRect(mx, my, x, y, w, h)
+	focused := c.isFocused(id)
+
+	if hovered {
+		c.setHot(id)
+	}
+
+	clicked := false
+	if hovered && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
+		c.setActive(id)
+		c.setFocused(id)
+		focused = true
+	}
+	if c.isActive(id) && c.input.IsButtonJustReleased(input.MouseButtonLeft) {
+		clicked = hovered
+		c.clearActive()
+	}
+	if focused && (c.input.IsKeyJustPressed(input.KeyEnter) || c.input.IsKeyJustPressed(input.KeySpace)) {
+		clicked = true
+	}
+
+	bgColor := c.color(ColorItemHovered)
+	if selected {
+		bgColor = c.color(ColorSelection)
+	}
+	switch {
+	case focused:
+		c.roundedRectOutline(x, y, w, h, c.style.FrameRounding, 1, bgColor, c.color(ColorFocusRing))
+	case selected || hovered || c.isActive(id):
+		c.filledRect(x, y, w, h, bgColor)
+	}
+
+	fontSize := c.style.FontSize
+	c.text(label, x+6, y+(h-int(fontSize))/2, fontSize, c.color(ColorText))
+
+	return clicked
+}
@@ -8,6 +8,8 @@ import (
 // DefaultInputContext wraps hlg's input functions to implement InputContext.
 type DefaultInputContext struct {
 	charInput []rune
+
+	scrollX, scrollY float64 // wheel movement this frame
 }
 
 // NewDefaultInputContext creates an InputContext that uses hlg's input functions.
@@ -45,12 +47,38 @@ func (d *DefaultInputContext) IsKeyJustPressed(key input.Key) bool {
 	return hlg.IsKeyJustPressed(key)
 }
 
+// IsKeyRepeated returns true if the key repeated this frame because it is held.
+func (d *DefaultInputContext) IsKeyRepeated(key input.Key) bool {
+	return hlg.IsKeyRepeated(key)
+}
+
 // GetCharInput returns runes typed this frame.
 func (d *DefaultInputContext) GetCharInput() []rune {
 	return d.charInput
 }
 
+// ScrollDelta returns the mouse wheel movement this frame.
+func (d *DefaultInputContext) ScrollDelta() (x, y float64) {
+	return d.scrollX, d.scrollY
+}
+
+// GetClipboardText returns the text on the system clipboard.
+func (d *DefaultInputContext) GetClipboardText() string {
+	return hlg.GetClipboardText()
+}
+
+// SetClipboardText puts text on the system clipboard.
+func (d *DefaultInputContext) SetClipboardText(text string) {
+	hlg.SetClipboardText(text)
+}
+
+// SetCursorShape shows a standard cursor over the window.
+func (d *DefaultInputContext) SetCursorShape(shape input.CursorShape) {
+	hlg.SetCursorShape(shape)
+}
+
 // Update must be called once per frame to update input state.
 func (d *DefaultInputContext) Update() {
 	d.charInput = hlg.GetTypedRunes()
+	d.scrollX, d.scrollY = hlg.GetScrollDelta()
 }
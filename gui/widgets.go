@@ -1,11 +1,6 @@
 package gui
 
-import (
-	"image/color"
-
-	"github.com/dfirebaugh/hlg"
-	"github.com/dfirebaugh/hlg/pkg/input"
-)
+import "github.com/dfirebaugh/hlg/pkg/input"
 
 // Button renders a button and returns true if clicked.
 func (c *Context) Button(label string, x, y, w, h int) bool {
@@ -40,23 +35,25 @@ func (c *Context) Button(label string, x, y, w, h int) bool {
 		}
 	}
 
-	bgColor := color.RGBA{70, 70, 80, 255}
+	bgColor := c.color(ColorButton)
 	if c.isActive(id) {
-		bgColor = color.RGBA{50, 50, 60, 255}
+		bgColor = c.color(ColorButtonActive)
 	} else if hovered || focused {
-		bgColor = color.RGBA{90, 90, 100, 255}
+		bgColor = c.color(ColorButtonHovered)
 	}
 
-	hlg.RoundedRect(x, y, w, h, 4, bgColor)
+	rounding := c.style.FrameRounding
+	c.roundedRect(x, y, w, h, rounding, bgColor)
 
 	if focused {
-		hlg.RoundedRectOutline(x+1, y+1, w-2, h-2, 3, 1, color.RGBA{100, 150, 255, 255}, color.RGBA{100, 150, 255, 255})
+		focusColor := c.color(ColorFocusRing)
+		c.roundedRectOutline(x+1, y+1, w-2, h-2, max(rounding-1, 0), 1, focusColor, focusColor)
 	}
 
-	textColor := color.RGBA{220, 220, 220, 255}
-	textX := x + (w-int(hlg.MeasureText(label, 14)))/2
-	textY := y + (h-14)/2 + 2
-	hlg.Text(label, textX, textY, 14, textColor)
+	fontSize := c.style.FontSize
+	textX := x + (w-int(c.renderer.MeasureText(label, fontSize)))/2
+	textY := y + (h-int(fontSize))/2 + 2
+	c.text(label, textX, textY, fontSize, c.color(ColorText))
 
 	return clicked
 }
@@ -99,25 +96,26 @@ func (c *Context) Checkbox(label string, checked *bool, x, y, size int) bool {
 		}
 	}
 
-	boxColor := color.RGBA{60, 60, 70, 255}
+	boxColor := c.color(ColorCheckbox)
 	if hovered || focused {
-		boxColor = color.RGBA{80, 80, 90, 255}
+		boxColor = c.color(ColorCheckboxHovered)
 	}
 
-	hlg.RoundedRect(x, y, size, size, 3, boxColor)
+	rounding := max(c.style.FrameRounding-1, 0)
+	c.roundedRect(x, y, size, size, rounding, boxColor)
 
 	if focused {
-		hlg.RoundedRectOutline(x+2, y+2, size-4, size-4, 1, 2, color.RGBA{100, 150, 255, 255}, color.RGBA{100, 150, 255, 255})
+		focusColor := c.color(ColorFocusRing)
+		c.roundedRectOutline(x+2, y+2, size-4, size-4, max(rounding-2, 0), 2, focusColor, focusColor)
 	}
 
 	if *checked {
-		checkColor := color.RGBA{100, 200, 100, 255}
 		margin := size / 4
-		hlg.RoundedRect(x+margin, y+margin, size-margin*2, size-margin*2, 2, checkColor)
+		c.roundedRect(x+margin, y+margin, size-margin*2, size-margin*2, rounding*2/3, c.color(ColorCheckMark))
 	}
 
-	textColor := color.RGBA{220, 220, 220, 255}
-	hlg.Text(label, x+size+8, y+(size-14)/2, 14, textColor)
+	fontSize := c.style.FontSize
+	c.text(label, x+size+8, y+(size-int(fontSize))/2, fontSize, c.color(ColorText))
 
 	return changed
 }
@@ -158,16 +156,17 @@ func (c *Context) Toggle(label string, on *bool, x, y, w, h int) bool {
 		}
 	}
 
-	trackColor := color.RGBA{60, 60, 70, 255}
+	trackColor := c.color(ColorToggle)
 	if *on {
-		trackColor = color.RGBA{60, 140, 80, 255}
+		trackColor = c.color(ColorToggleOn)
 	}
 
 	radius := h / 2
-	hlg.RoundedRect(x, y, w, h, radius, trackColor)
+	c.roundedRect(x, y, w, h, radius, trackColor)
 
 	if focused {
-		hlg.RoundedRectOutline(x+2, y+2, w-4, h-4, radius-2, 2, color.RGBA{100, 150, 255, 255}, color.RGBA{100, 150, 255, 255})
+		focusColor := c.color(ColorFocusRing)
+		c.roundedRectOutline(x+2, y+2, w-4, h-4, radius-2, 2, focusColor, focusColor)
 	}
 
 	knobSize := h - 4
@@ -176,12 +175,12 @@ func (c *Context) Toggle(label string, on *bool, x, y, w, h int) bool {
 		knobX = x + w - knobSize - 2
 	}
 
-	knobColor := color.RGBA{220, 220, 220, 255}
+	knobColor := c.color(ColorToggleKnob)
 	if hovered {
-		knobColor = color.RGBA{255, 255, 255, 255}
+		knobColor = c.color(ColorToggleKnobHovered)
 	}
 
-	hlg.RoundedRect(knobX, y+2, knobSize, knobSize, knobSize/2, knobColor)
+	c.roundedRect(knobX, y+2, knobSize, knobSize, knobSize/2, knobColor)
 
 	return changed
 }
@@ -245,127 +244,47 @@ func (c *Context) Slider(label string, value *float32, min, max float32, x, y, w
 		}
 	}
 
-	trackColor := color.RGBA{50, 50, 60, 255}
-	hlg.RoundedRect(x, y+2, w, h, h/2, trackColor)
+	c.roundedRect(x, y+2, w, h, h/2, c.color(ColorSliderTrack))
 
 	if focused {
-		hlg.RoundedRectOutline(x+2, y+4, w-4, h-4, h/2-2, 2, color.RGBA{100, 150, 255, 255}, color.RGBA{100, 150, 255, 255})
+		focusColor := c.color(ColorFocusRing)
+		c.roundedRectOutline(x+2, y+4, w-4, h-4, h/2-2, 2, focusColor, focusColor)
 	}
 
 	ratio := (*value - min) / (max - min)
 	filledWidth := int(float32(w) * ratio)
 	if filledWidth > 0 {
-		fillColor := color.RGBA{80, 140, 200, 255}
-		hlg.RoundedRect(x, y+2, filledWidth, h, h/2, fillColor)
+		c.roundedRect(x, y+2, filledWidth, h, h/2, c.color(ColorSliderFill))
 	}
 
 	handleRadius := h + 4
 	handleX := x + int(float32(w)*ratio) - handleRadius/2
 	handleY := y
 
-	handleColor := color.RGBA{200, 200, 210, 255}
+	handleColor := c.color(ColorSliderHandle)
 	if c.isActive(id) || hovered {
-		handleColor = color.RGBA{240, 240, 250, 255}
+		handleColor = c.color(ColorSliderHandleActive)
 	}
 
-	hlg.RoundedRect(handleX, handleY, handleRadius, handleRadius, handleRadius/2, handleColor)
+	c.roundedRect(handleX, handleY, handleRadius, handleRadius, handleRadius/2, handleColor)
 
 	return changed
 }
 
-// Panel renders a draggable panel with a title bar.
-// The label is used for both the title and widget identification.
-// Returns true if the panel is expanded (not collapsed).
-// The state parameter holds position and collapse state.
-func (c *Context) Panel(label string, state *PanelState, w, h int) bool {
-	id := c.GetID(label + "_panel")
-	titleBarHeight := 28
-
-	actualHeight := h
-	if state.Collapsed {
-		actualHeight = titleBarHeight
-	}
-
-	c.registerPanelBounds(id, state.X, state.Y, w, actualHeight)
-
-	mx, my := c.input.MousePosition()
+// ProgressBar renders a bar filled to fraction, from 0 to 1, with overlay drawn centered
+// on it, such as "42%". Pass "" for no overlay.
+func (c *Context) ProgressBar(fraction float32, overlay string, x, y, w, h int) {
+	fraction = min(max(fraction, 0), 1)
+	rounding := min(c.style.FrameRounding, h/2)
+	c.roundedRect(x, y, w, h, rounding, c.color(ColorSliderTrack))
 
-	blocked := c.isBlockedByLaterPanel(id, mx, my)
-
-	collapseButtonX := state.X + w - 30
-	collapseButtonW := 28
-	collapseHovered := !blocked && pointInRect(mx, my, collapseButtonX, state.Y, collapseButtonW, titleBarHeight)
-
-	titleHovered := !blocked && pointInRect(mx, my, state.X, state.Y, w-collapseButtonW, titleBarHeight)
-
-	if titleHovered || collapseHovered {
-		c.setHot(id)
+	if filledWidth := int(float32(w) * fraction); filledWidth > 0 {
+		c.roundedRect(x, y, filledWidth, h, min(rounding, filledWidth/2), c.color(ColorSliderFill))
 	}
 
-	if collapseHovered && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
-		state.Collapsed = !state.Collapsed
+	if overlay != "" {
+		fontSize := c.style.FontSize
+		textX := x + (w-int(c.renderer.MeasureText(overlay, fontSize)))/2
+		c.text(overlay, textX, y+(h-int(fontSize))/2, fontSize, c.color(ColorText))
 	}
-
-	if titleHovered && c.input.IsButtonJustPressed(input.MouseButtonLeft) {
-		c.setActive(id)
-		state.dragging = true
-		state.dragOffX = mx - state.X
-		state.dragOffY = my - state.Y
-	}
-
-	if state.dragging {
-		if c.input.IsButtonPressed(input.MouseButtonLeft) {
-			state.X = mx - state.dragOffX
-			state.Y = my - state.dragOffY
-
-			sw, sh := hlg.GetScreenSize()
-			if state.X < 0 {
-				state.X = 0
-			}
-			if state.Y < 0 {
-				state.Y = 0
-			}
-			if state.X+w > sw {
-				state.X = sw - w
-			}
-			if state.Y+titleBarHeight > sh {
-				state.Y = sh - titleBarHeight
-			}
-		} else {
-			state.dragging = false
-			c.clearActive()
-		}
-	}
-
-	bgColor := color.RGBA{45, 45, 50, 240}
-	titleBgColor := color.RGBA{55, 55, 65, 255}
-	if c.isActive(id) || titleHovered {
-		titleBgColor = color.RGBA{65, 65, 75, 255}
-	}
-	titleTextColor := color.RGBA{220, 220, 225, 255}
-
-	hlg.RoundedRect(state.X, state.Y, w, actualHeight, 8, bgColor)
-
-	hlg.RoundedRect(state.X, state.Y, w, titleBarHeight, 8, titleBgColor)
-	if !state.Collapsed {
-		hlg.FilledRect(state.X, state.Y+titleBarHeight-8, w, 8, titleBgColor)
-	}
-
-	hlg.Text(label, state.X+10, state.Y+10, 14, titleTextColor)
-
-	indicatorX := state.X + w - 20
-	indicatorY := state.Y + 10
-	indicatorColor := color.RGBA{150, 150, 160, 255}
-	if collapseHovered {
-		indicatorColor = color.RGBA{200, 200, 210, 255}
-	}
-	if state.Collapsed {
-		hlg.FilledTriangle(indicatorX, indicatorY, indicatorX, indicatorY+8, indicatorX+6, indicatorY+4, indicatorColor)
-		c.ClearCurrentPanel()
-	} else {
-		hlg.FilledTriangle(indicatorX, indicatorY, indicatorX+8, indicatorY, indicatorX+4, indicatorY+6, indicatorColor)
-		c.SetCurrentPanel(id)
-	}
-
-	return !state.Collapsed
 }
@@ -104,7 +104,7 @@ func run(updateFn func(), renderFn func()) {
 
 	hlg.graphicsBackend.SetInputCallback(func(eventChan chan input.Event) {
 		evt := <-eventChan
-		handleEvent(evt, hlg.inputState)
+		dispatchEvent(evt, hlg.inputState)
 	})
 	targetFPS := 120.0
 	targetFrameDuration := time.Second / time.Duration(targetFPS)
@@ -133,6 +133,7 @@ func run(updateFn func(), renderFn func()) {
 				renderFn()
 			}
 			hlg.graphicsBackend.Render()
+			resetTextStyles()
 
 			calculateFPS()
 
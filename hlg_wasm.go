@@ -93,7 +93,7 @@ func run(updateFn func(), renderFn func()) {
 
 	hlg.graphicsBackend.SetInputCallback(func(eventChan chan input.Event) {
 		evt := <-eventChan
-		handleEvent(evt, hlg.inputState)
+		dispatchEvent(evt, hlg.inputState)
 	})
 
 	// Use requestAnimationFrame for the game loop
@@ -114,6 +114,7 @@ func run(updateFn func(), renderFn func()) {
 			renderFn()
 		}
 		hlg.graphicsBackend.Render()
+		resetTextStyles()
 
 		calculateFPS()
 		hlg.inputState.ResetJustPressed()
@@ -11,6 +11,17 @@ func IsKeyJustPressed(keyCode input.Key) bool {
 	return hlg.inputState.IsKeyJustPressed(keyCode)
 }
 
+// IsKeyRepeated checks if a held key sent a repeat this frame
+func IsKeyRepeated(keyCode input.Key) bool {
+	return hlg.inputState.IsKeyRepeated(keyCode)
+}
+
+// ModifiersHeld checks if all of the given modifier keys are held, e.g.
+// ModifiersHeld(input.ModControl) for Ctrl+S shortcuts
+func ModifiersHeld(mods input.ModifierKey) bool {
+	return hlg.inputState.ModifiersHeld(mods)
+}
+
 func PressKey(keyCode input.Key) {
 	hlg.inputState.PressKey(keyCode)
 }
@@ -50,7 +61,33 @@ func SetScrollCallback(cb func(x float64, y float64)) {
 	hlg.inputState.SetScrollCallback(cb)
 }
 
+// GetMouseDelta returns the mouse movement of this frame in window pixels. Unlike the
+// cursor position it keeps changing while the cursor is locked, for mouse look.
+func GetMouseDelta() (dx, dy float64) {
+	return hlg.inputState.GetMouseDelta()
+}
+
+// GetScrollDelta returns the mouse wheel movement of this frame, on both axes.
+// Positive y scrolls up and positive x scrolls left.
+func GetScrollDelta() (x, y float64) {
+	return hlg.inputState.GetScrollDelta()
+}
+
 // GetTypedRunes returns the runes typed this frame
 func GetTypedRunes() []rune {
 	return hlg.inputState.GetTypedRunes()
 }
+
+// Touches returns the touches held on a touch screen and those that ended this frame,
+// in the order they began. Touches are reported in browsers; on desktop, GLFW reports
+// touch screens as a mouse.
+func Touches() []input.Touch {
+	return hlg.inputState.GetTouches()
+}
+
+// SetTouchMouseEmulation sets whether the first touch held also moves the cursor and
+// presses the left mouse button, so mouse-driven games and the gui work on touch
+// screens. It is enabled by default.
+func SetTouchMouseEmulation(enabled bool) {
+	hlg.inputState.EmulateMouse = enabled
+}
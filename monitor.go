@@ -0,0 +1,63 @@
+package hlg
+
+import "github.com/dfirebaugh/hlg/graphics"
+
+// Monitor describes a display connected to the system.
+type Monitor = graphics.Monitor
+
+// VideoMode is a resolution and refresh rate a monitor can be driven at.
+type VideoMode = graphics.VideoMode
+
+// Monitors returns the monitors connected, the primary monitor first. In browsers it
+// returns the screen the page is shown on.
+func Monitors() []Monitor {
+	ensureSetupCompletion()
+	return hlg.graphicsBackend.Monitors()
+}
+
+// SetFullscreen makes the window fullscreen on a monitor, an index into Monitors, in
+// one of its Modes. A zero VideoMode keeps the monitor's current mode, which is the
+// quickest to switch to. In browsers the page goes fullscreen, which they only allow
+// while handling a click or key press.
+func SetFullscreen(monitor int, mode VideoMode) {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetFullscreen(monitor, mode)
+}
+
+// SetWindowed leaves fullscreen, restoring the window's position and size.
+func SetWindowed() {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetWindowed()
+}
+
+// IsFullscreen returns true if the window is fullscreen.
+func IsFullscreen() bool {
+	ensureSetupCompletion()
+	return hlg.graphicsBackend.IsFullscreen()
+}
+
+// ToggleFullscreen switches between windowed and fullscreen on the monitor the window
+// is on, as for Alt+Enter:
+//
+//	if hlg.ModifiersHeld(input.ModAlt) && hlg.IsKeyJustPressed(input.KeyEnter) {
+//	    hlg.ToggleFullscreen()
+//	}
+func ToggleFullscreen() {
+	if IsFullscreen() {
+		SetWindowed()
+		return
+	}
+	SetFullscreen(currentMonitor(), VideoMode{})
+}
+
+// currentMonitor returns the index of the monitor the window's top left corner is on,
+// or -1 for the primary monitor if it is on none.
+func currentMonitor() int {
+	x, y := hlg.graphicsBackend.GetWindowPosition()
+	for i, m := range hlg.graphicsBackend.Monitors() {
+		if x >= m.X && x < m.X+m.Width && y >= m.Y && y < m.Y+m.Height {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,343 @@
+package bmfont
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Font holds the contents of a BMFont descriptor.
+// Page images are referenced by file name and are not loaded by this package.
+type Font struct {
+	Face       string
+	Size       int
+	LineHeight int
+	Base       int // distance from the top of a line to the baseline
+	ScaleW     int // width of the page textures
+	ScaleH     int // height of the page textures
+	Pages      []string
+	Chars      map[rune]Char
+	Kernings   map[KerningPair]int
+
+	pageCount int // pages declared by the common block, 0 if not declared
+}
+
+// maxPages bounds the page ids a descriptor may use; the binary format stores the page of
+// a character in one byte.
+const maxPages = 256
+
+// Char describes where a glyph lives on its page and how it is placed.
+type Char struct {
+	ID       rune
+	X, Y     int
+	Width    int
+	Height   int
+	XOffset  int
+	YOffset  int
+	XAdvance int
+	Page     int
+}
+
+// KerningPair identifies two adjacent characters.
+type KerningPair struct {
+	First, Second rune
+}
+
+// Kerning returns the horizontal adjustment applied between first and second.
+func (f *Font) Kerning(first, second rune) int {
+	return f.Kernings[KerningPair{First: first, Second: second}]
+}
+
+// Parse detects the descriptor format and parses it.
+func Parse(data []byte) (*Font, error) {
+	trimmed := bytes.TrimLeft(data, " \t\r\n\xef\xbb\xbf")
+	switch {
+	case bytes.HasPrefix(data, []byte("BMF")):
+		return ParseBinary(data)
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return ParseXML(data)
+	default:
+		return ParseText(data)
+	}
+}
+
+func newFont() *Font {
+	return &Font{
+		Chars:    make(map[rune]Char),
+		Kernings: make(map[KerningPair]int),
+	}
+}
+
+// ParseText parses the text variant of the format.
+func ParseText(data []byte) (*Font, error) {
+	f := newFont()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		tag, attrs := splitTextLine(scanner.Text())
+		if tag == "" {
+			continue
+		}
+		if err := f.applyTag(tag, attrs); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read font: %w", err)
+	}
+	return f, f.validate()
+}
+
+// splitTextLine splits a line like `char id=65 x=0` into its tag and key/value pairs.
+// Quoted values may contain spaces.
+func splitTextLine(line string) (string, map[string]string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", nil
+	}
+	tag, rest, _ := strings.Cut(line, " ")
+	attrs := make(map[string]string)
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.TrimSpace(key)
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				attrs[key] = value[1:]
+				break
+			}
+			attrs[key] = value[1 : end+1]
+			rest = value[end+2:]
+			continue
+		}
+		value, rest, _ = strings.Cut(value, " ")
+		attrs[key] = value
+	}
+	return tag, attrs
+}
+
+type xmlAttrs []xml.Attr
+
+func (a xmlAttrs) toMap() map[string]string {
+	m := make(map[string]string, len(a))
+	for _, attr := range a {
+		m[attr.Name.Local] = attr.Value
+	}
+	return m
+}
+
+// ParseXML parses the XML variant of the format.
+func ParseXML(data []byte) (*Font, error) {
+	f := newFont()
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse xml: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "info", "common", "page", "char", "kerning":
+			if err := f.applyTag(start.Name.Local, xmlAttrs(start.Attr).toMap()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return f, f.validate()
+}
+
+func (f *Font) applyTag(tag string, attrs map[string]string) error {
+	num := func(key string) int {
+		n, _ := strconv.Atoi(attrs[key])
+		return n
+	}
+
+	switch tag {
+	case "info":
+		f.Face = attrs["face"]
+		f.Size = num("size")
+		if f.Size < 0 {
+			// Negative sizes mean the size matched the cell height instead of the character height
+			f.Size = -f.Size
+		}
+	case "common":
+		f.LineHeight = num("lineHeight")
+		f.Base = num("base")
+		f.ScaleW = num("scaleW")
+		f.ScaleH = num("scaleH")
+		f.pageCount = num("pages")
+		if f.pageCount < 0 {
+			return fmt.Errorf("invalid page count %d", f.pageCount)
+		}
+	case "page":
+		id := num("id")
+		if err := f.checkPage(id); err != nil {
+			return err
+		}
+		for len(f.Pages) <= id {
+			f.Pages = append(f.Pages, "")
+		}
+		f.Pages[id] = attrs["file"]
+	case "char":
+		c := Char{
+			ID:       rune(num("id")),
+			X:        num("x"),
+			Y:        num("y"),
+			Width:    num("width"),
+			Height:   num("height"),
+			XOffset:  num("xoffset"),
+			YOffset:  num("yoffset"),
+			XAdvance: num("xadvance"),
+			Page:     num("page"),
+		}
+		f.Chars[c.ID] = c
+	case "kerning":
+		pair := KerningPair{First: rune(num("first")), Second: rune(num("second"))}
+		f.Kernings[pair] = num("amount")
+	}
+	return nil
+}
+
+// checkPage returns an error if id is not a page the font may have, so a bad id cannot
+// grow Pages without bound.
+func (f *Font) checkPage(id int) error {
+	limit := maxPages
+	if f.pageCount > 0 {
+		limit = min(f.pageCount, maxPages)
+	}
+	if id < 0 || id >= limit {
+		return fmt.Errorf("invalid page id %d", id)
+	}
+	return nil
+}
+
+// Block types of the binary format.
+const (
+	blockInfo     = 1
+	blockCommon   = 2
+	blockPages    = 3
+	blockChars    = 4
+	blockKernings = 5
+)
+
+// ParseBinary parses the binary (version 3) variant of the format.
+func ParseBinary(data []byte) (*Font, error) {
+	if len(data) < 4 || string(data[:3]) != "BMF" {
+		return nil, errors.New("not a binary BMFont file")
+	}
+	if data[3] != 3 {
+		return nil, fmt.Errorf("unsupported binary BMFont version %d", data[3])
+	}
+
+	f := newFont()
+	le := binary.LittleEndian
+	pos := 4
+	for pos < len(data) {
+		if pos+5 > len(data) {
+			return nil, errors.New("truncated block header")
+		}
+		blockType := data[pos]
+		blockSize := int(le.Uint32(data[pos+1:]))
+		pos += 5
+		if blockSize < 0 || pos+blockSize > len(data) {
+			return nil, fmt.Errorf("block %d overruns file", blockType)
+		}
+		block := data[pos : pos+blockSize]
+		pos += blockSize
+
+		switch blockType {
+		case blockInfo:
+			if len(block) < 14 {
+				return nil, errors.New("truncated info block")
+			}
+			size := int(int16(le.Uint16(block[0:])))
+			if size < 0 {
+				size = -size
+			}
+			f.Size = size
+			f.Face = cString(block[14:])
+		case blockCommon:
+			if len(block) < 10 {
+				return nil, errors.New("truncated common block")
+			}
+			f.LineHeight = int(le.Uint16(block[0:]))
+			f.Base = int(le.Uint16(block[2:]))
+			f.ScaleW = int(le.Uint16(block[4:]))
+			f.ScaleH = int(le.Uint16(block[6:]))
+			f.pageCount = int(le.Uint16(block[8:]))
+		case blockPages:
+			for len(block) > 0 {
+				if err := f.checkPage(len(f.Pages)); err != nil {
+					return nil, err
+				}
+				name := cString(block)
+				f.Pages = append(f.Pages, name)
+				block = block[min(len(name)+1, len(block)):]
+			}
+		case blockChars:
+			const charSize = 20
+			for i := 0; i+charSize <= len(block); i += charSize {
+				b := block[i:]
+				c := Char{
+					ID:       rune(le.Uint32(b[0:])),
+					X:        int(le.Uint16(b[4:])),
+					Y:        int(le.Uint16(b[6:])),
+					Width:    int(le.Uint16(b[8:])),
+					Height:   int(le.Uint16(b[10:])),
+					XOffset:  int(int16(le.Uint16(b[12:]))),
+					YOffset:  int(int16(le.Uint16(b[14:]))),
+					XAdvance: int(int16(le.Uint16(b[16:]))),
+					Page:     int(b[18]),
+				}
+				f.Chars[c.ID] = c
+			}
+		case blockKernings:
+			const kerningSize = 10
+			for i := 0; i+kerningSize <= len(block); i += kerningSize {
+				b := block[i:]
+				pair := KerningPair{First: rune(le.Uint32(b[0:])), Second: rune(le.Uint32(b[4:]))}
+				f.Kernings[pair] = int(int16(le.Uint16(b[8:])))
+			}
+		}
+	}
+	return f, f.validate()
+}
+
+// cString returns the bytes up to the first NUL as a string.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		return string(b[:i])
+	}
+	return string(b)
+}
+
+func (f *Font) validate() error {
+	if len(f.Chars) == 0 {
+		return errors.New("font has no characters")
+	}
+	if len(f.Pages) == 0 {
+		return errors.New("font has no pages")
+	}
+	for _, c := range f.Chars {
+		if c.Page < 0 || c.Page >= len(f.Pages) {
+			return fmt.Errorf("character %d references missing page %d", c.ID, c.Page)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package bmfont
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+const textFont = `info face="Pixel Sans" size=-16 bold=0
+common lineHeight=18 base=14 scaleW=128 scaleH=64 pages=2 packed=0
+page id=0 file="pixel_0.png"
+page id=1 file="pixel_1.png"
+chars count=2
+char id=65 x=1 y=2 width=7 height=9 xoffset=0 yoffset=3 xadvance=8 page=0 chnl=15
+char id=66 x=9 y=2 width=7 height=9 xoffset=-1 yoffset=3 xadvance=8 page=1 chnl=15
+kernings count=1
+kerning first=65 second=66 amount=-1
+`
+
+const xmlFont = `<?xml version="1.0"?>
+<font>
+  <info face="Pixel Sans" size="-16"/>
+  <common lineHeight="18" base="14" scaleW="128" scaleH="64" pages="2"/>
+  <pages>
+    <page id="0" file="pixel_0.png"/>
+    <page id="1" file="pixel_1.png"/>
+  </pages>
+  <chars count="2">
+    <char id="65" x="1" y="2" width="7" height="9" xoffset="0" yoffset="3" xadvance="8" page="0"/>
+    <char id="66" x="9" y="2" width="7" height="9" xoffset="-1" yoffset="3" xadvance="8" page="1"/>
+  </chars>
+  <kernings count="1">
+    <kerning first="65" second="66" amount="-1"/>
+  </kernings>
+</font>
+`
+
+// binaryFont builds a binary descriptor of the font in textFont. pageNames is the
+// contents of the pages block and pageCount the pages the common block declares.
+func binaryFont(pageNames string, pageCount int) []byte {
+	le := binary.LittleEndian
+	var buf bytes.Buffer
+	buf.WriteString("BMF\x03")
+	block := func(blockType byte, data []byte) {
+		buf.WriteByte(blockType)
+		buf.Write(le.AppendUint32(nil, uint32(len(data))))
+		buf.Write(data)
+	}
+
+	info := make([]byte, 14)
+	le.PutUint16(info[0:], uint16(0xffff-15)) // -16
+	block(blockInfo, append(info, "Pixel Sans\x00"...))
+
+	common := make([]byte, 15)
+	le.PutUint16(common[0:], 18)
+	le.PutUint16(common[2:], 14)
+	le.PutUint16(common[4:], 128)
+	le.PutUint16(common[6:], 64)
+	le.PutUint16(common[8:], uint16(pageCount))
+	block(blockCommon, common)
+
+	block(blockPages, []byte(pageNames))
+
+	var chars []byte
+	for _, c := range []Char{
+		{ID: 65, X: 1, Y: 2, Width: 7, Height: 9, YOffset: 3, XAdvance: 8},
+		{ID: 66, X: 9, Y: 2, Width: 7, Height: 9, XOffset: -1, YOffset: 3, XAdvance: 8, Page: 1},
+	} {
+		b := make([]byte, 20)
+		le.PutUint32(b[0:], uint32(c.ID))
+		le.PutUint16(b[4:], uint16(c.X))
+		le.PutUint16(b[6:], uint16(c.Y))
+		le.PutUint16(b[8:], uint16(c.Width))
+		le.PutUint16(b[10:], uint16(c.Height))
+		le.PutUint16(b[12:], uint16(int16(c.XOffset)))
+		le.PutUint16(b[14:], uint16(int16(c.YOffset)))
+		le.PutUint16(b[16:], uint16(int16(c.XAdvance)))
+		b[18] = byte(c.Page)
+		chars = append(chars, b...)
+	}
+	block(blockChars, chars)
+
+	kerning := make([]byte, 10)
+	le.PutUint32(kerning[0:], 65)
+	le.PutUint32(kerning[4:], 66)
+	le.PutUint16(kerning[8:], uint16(0xffff)) // -1
+	block(blockKernings, kerning)
+	return buf.Bytes()
+}
+
+var validBinary = binaryFont("pixel_0.png\x00pixel_1.png\x00", 2)
+
+// checkFont compares a parsed font with the font every valid descriptor here describes.
+func checkFont(t *testing.T, f *Font) {
+	t.Helper()
+	if f.Face != "Pixel Sans" || f.Size != 16 || f.LineHeight != 18 || f.Base != 14 || f.ScaleW != 128 || f.ScaleH != 64 {
+		t.Errorf("header: face %q size %d line height %d base %d scale %dx%d",
+			f.Face, f.Size, f.LineHeight, f.Base, f.ScaleW, f.ScaleH)
+	}
+	if len(f.Pages) != 2 || f.Pages[0] != "pixel_0.png" || f.Pages[1] != "pixel_1.png" {
+		t.Errorf("pages %q", f.Pages)
+	}
+	want := Char{ID: 66, X: 9, Y: 2, Width: 7, Height: 9, XOffset: -1, YOffset: 3, XAdvance: 8, Page: 1}
+	if len(f.Chars) != 2 || f.Chars[66] != want {
+		t.Errorf("%d chars, B is %+v", len(f.Chars), f.Chars[66])
+	}
+	if k := f.Kerning('A', 'B'); k != -1 {
+		t.Errorf("kerning A B is %d, want -1", k)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"text", []byte(textFont)},
+		{"text with CRLF", []byte(strings.ReplaceAll(textFont, "\n", "\r\n"))},
+		{"xml", []byte(xmlFont)},
+		{"xml with BOM", []byte("\xef\xbb\xbf" + xmlFont)},
+		{"binary", validBinary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := Parse(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkFont(t, f)
+		})
+	}
+}
+
+func TestParseText(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"empty", "", "no characters"},
+		{"no pages", "char id=65 page=0", "no pages"},
+		{"missing page", "page id=0 file=a.png\nchar id=65 page=1", "missing page 1"},
+		{"negative page id", "page id=-1 file=a.png", "invalid page id -1"},
+		{"page id past the declared pages", "common pages=1\npage id=1 file=a.png", "invalid page id 1"},
+		{"huge page id", "page id=2000000000 file=a.png", "invalid page id 2000000000"},
+		{"negative page count", "common pages=-1", "invalid page count -1"},
+		{"unterminated quote", `info face="Pixel` + "\npage id=0 file=a.png\nchar id=65", ""},
+		{"truncated line", "page id=0 file=a.png\nchar id=65 x=", ""},
+		{"undeclared page count", "page id=255 file=a.png\nchar id=65 page=255", ""},
+		{"unknown tags", "page id=0 file=a.png\nchar id=65\nfoo bar=1\n\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseText([]byte(tt.data))
+			checkErr(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseXML(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"truncated", xmlFont[:len(xmlFont)/2], "failed to parse xml"},
+		{"mismatched tags", `<font><pages></font>`, "failed to parse xml"},
+		{"no characters", `<font><page id="0" file="a.png"/></font>`, "no characters"},
+		{"page id past the declared pages", `<font><common pages="2"/><page id="2" file="a.png"/></font>`, "invalid page id 2"},
+		{"huge page id", `<font><page id="99999999" file="a.png"/></font>`, "invalid page id 99999999"},
+		{"missing page", `<font><page id="0" file="a.png"/><char id="65" page="3"/></font>`, "missing page 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseXML([]byte(tt.data))
+			checkErr(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"not binary", []byte("info face=x"), "not a binary BMFont file"},
+		{"only the magic", []byte("BMF"), "not a binary BMFont file"},
+		{"unsupported version", []byte("BMF\x02"), "unsupported binary BMFont version 2"},
+		{"truncated block header", []byte("BMF\x03\x01\x00"), "truncated block header"},
+		{"block overruns file", []byte("BMF\x03\x01\xff\x00\x00\x00"), "block 1 overruns file"},
+		{"truncated info block", []byte("BMF\x03\x01\x02\x00\x00\x00ab"), "truncated info block"},
+		{"truncated common block", []byte("BMF\x03\x02\x02\x00\x00\x00ab"), "truncated common block"},
+		{"more pages than declared", binaryFont("a.png\x00b.png\x00c.png\x00", 2), "invalid page id 2"},
+		{"too many pages", binaryFont(strings.Repeat("\x00", maxPages+1), 0), "invalid page id 256"},
+		{"missing page", binaryFont("a.png\x00", 1), "missing page 1"},
+		{"unterminated page name", binaryFont("a.png\x00b.png", 2), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBinary(tt.data)
+			checkErr(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestParseBinaryTruncated(t *testing.T) {
+	// Ends of the blocks of the valid file, where it can be cut without cutting a block
+	boundaries := map[int]bool{}
+	for pos := 4; pos < len(validBinary); {
+		pos += 5 + int(binary.LittleEndian.Uint32(validBinary[pos+1:]))
+		boundaries[pos] = true
+	}
+	for n := range validBinary {
+		_, err := ParseBinary(validBinary[:n])
+		if err == nil && !boundaries[n] {
+			t.Errorf("file cut to %d bytes, within a block, parsed", n)
+		}
+	}
+}
+
+func checkErr(t *testing.T, err error, want string) {
+	t.Helper()
+	switch {
+	case want == "" && err != nil:
+		t.Fatalf("unexpected error: %v", err)
+	case want != "" && err == nil:
+		t.Fatalf("no error, want %q", want)
+	case want != "" && !strings.Contains(err.Error(), want):
+		t.Fatalf("error %q, want %q", err, want)
+	}
+}
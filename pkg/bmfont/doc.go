@@ -0,0 +1,3 @@
+// The bmfont package parses AngelCode BMFont descriptor files (.fnt).
+// The text, XML and binary (version 3) variants of the format are supported.
+package bmfont
@@ -0,0 +1,28 @@
+package input
+
+// CursorShape is a standard shape of the mouse cursor.
+type CursorShape int
+
+const (
+	CursorArrow      CursorShape = iota
+	CursorIBeam                  // text
+	CursorCrosshair              // precise selection
+	CursorHand                   // links and draggable items
+	CursorResizeH                // resizing horizontally
+	CursorResizeV                // resizing vertically
+	CursorResizeNWSE             // resizing from a bottom-right corner; an arrow on desktop, which GLFW 3.3 lacks
+)
+
+// CursorMode is how the mouse cursor behaves over the window.
+type CursorMode int
+
+const (
+	// CursorNormal shows the cursor and moves it freely.
+	CursorNormal CursorMode = iota
+	// CursorHidden hides the cursor while it is over the window.
+	CursorHidden
+	// CursorLocked hides the cursor and keeps it in the window, for mouse look. Read the
+	// movement with the mouse deltas, since the cursor position no longer means much.
+	// Browsers only lock the pointer during a click or key press, so set it from input.
+	CursorLocked
+)
@@ -1,5 +1,7 @@
 package input
 
+import "time"
+
 type EventType int
 
 const (
@@ -9,12 +11,60 @@ const (
 	MouseRelease
 	MouseMove
 	CharInput
+	MouseScroll
+	TouchStart
+	TouchMove
+	TouchEnd
+	TouchCancel
+	FilesDropped
+
+	// Window state changes. WindowMoved carries the new position of the window in X
+	// and Y, in screen coordinates.
+	WindowMoved
+	WindowMinimized
+	WindowMaximized
+	WindowRestored // no longer minimized or maximized
+	WindowFocused
+	WindowUnfocused
 )
 
+// DroppedFile is a file dropped onto the window.
+type DroppedFile struct {
+	Name string // file name, without directories
+	Path string // full path on desktop; empty in browsers, which do not reveal it
+	Data []byte // contents; read by the browser, and on desktop only when needed
+}
+
 type Event struct {
 	Type        EventType
 	Key         Key
 	MouseButton MouseButton
 	X, Y        int
 	Rune        rune
+
+	// DX and DY are the movement of a MouseMove event in window pixels, unscaled and
+	// reported even when the cursor is locked.
+	DX, DY float64
+
+	// ScrollX and ScrollY are the wheel or touchpad offsets of a MouseScroll event.
+	// Positive ScrollY scrolls up.
+	ScrollX, ScrollY float64
+
+	// Mods are the modifier keys held during a key or mouse button event.
+	Mods ModifierKey
+	// Scancode is the platform-specific code of the physical key of a key event, or 0
+	// where the platform does not report one, as in browsers.
+	Scancode int
+	// Repeat is set on the KeyPress events sent while a key is held down.
+	Repeat bool
+
+	// TouchID identifies the touch of a touch event among the touches held. X and Y
+	// are its position.
+	TouchID int
+
+	// Files are the files dropped onto the window by a FilesDropped event.
+	Files []DroppedFile
+
+	// Time is when the event was received.
+	Time time.Time
 }
@@ -0,0 +1,322 @@
+// Package gesture recognizes taps, double taps, long presses, swipes, pinches and
+// two-finger pans from a stream of mouse and touch events.
+//
+// A Recognizer is fed every input.Event, for example from hlg.OnEvent, and ticked each
+// frame so long presses fire while the pointer is held still:
+//
+//	r := gesture.NewRecognizer(gesture.DefaultConfig())
+//	hlg.OnEvent(func(evt input.Event) bool {
+//	    for _, g := range r.Feed(evt) {
+//	        switch g.Kind {
+//	        case gesture.Pinch:
+//	            zoom *= g.Scale
+//	        case gesture.Pan:
+//	            offsetX += g.DX
+//	            offsetY += g.DY
+//	        }
+//	    }
+//	    return false
+//	})
+//
+// The left mouse button is a single pointer, so mouse input gives taps, double taps,
+// long presses and swipes; pinches and pans need two touches.
+package gesture
+
+import (
+	"math"
+	"time"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+// Kind identifies a recognized gesture.
+type Kind int
+
+const (
+	Tap Kind = iota
+	DoubleTap
+	LongPress
+	Swipe
+	Pinch
+	Pan
+)
+
+var kindNames = [...]string{
+	Tap:       "Tap",
+	DoubleTap: "DoubleTap",
+	LongPress: "LongPress",
+	Swipe:     "Swipe",
+	Pinch:     "Pinch",
+	Pan:       "Pan",
+}
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "Kind(?)"
+	}
+	return kindNames[k]
+}
+
+// Direction is the main direction of a swipe.
+type Direction int
+
+const (
+	Left Direction = iota
+	Right
+	Up
+	Down
+)
+
+// Gesture is a recognized gesture. Only the fields of its kind are set.
+type Gesture struct {
+	Kind Kind
+	// X and Y are where a tap, double tap or long press happened, where a swipe began,
+	// or the point between the two touches of a pinch or pan, to zoom about.
+	X, Y float64
+	// DX and DY are the movement of a swipe from where it began, or of a pan since the
+	// last pan.
+	DX, DY float64
+	// Scale is the change of the distance between the touches of a pinch since the last
+	// pinch; multiply a zoom by it.
+	Scale float64
+	Time  time.Time
+}
+
+// Direction returns the main direction of a swipe, with y growing downwards.
+func (g Gesture) Direction() Direction {
+	if math.Abs(g.DX) >= math.Abs(g.DY) {
+		if g.DX < 0 {
+			return Left
+		}
+		return Right
+	}
+	if g.DY < 0 {
+		return Up
+	}
+	return Down
+}
+
+// Config holds the thresholds of a Recognizer. Distances are in logical pixels.
+type Config struct {
+	TapMaxDuration       time.Duration // longest press that is still a tap
+	TapMaxDistance       float64       // furthest a tap, double tap or long press may move
+	DoubleTapMaxInterval time.Duration // longest time between the taps of a double tap
+	LongPressDuration    time.Duration // time a still pointer is held for a long press
+	SwipeMinDistance     float64       // shortest movement that is a swipe
+	SwipeMaxDuration     time.Duration // longest press that is still a swipe
+	// TwoFingerMinDistance is how far two touches must move, together or apart, before
+	// they pinch and pan, so resting two fingers does not jitter a camera.
+	TwoFingerMinDistance float64
+}
+
+// DefaultConfig returns thresholds that suit fingers and mice.
+func DefaultConfig() Config {
+	return Config{
+		TapMaxDuration:       250 * time.Millisecond,
+		TapMaxDistance:       10,
+		DoubleTapMaxInterval: 300 * time.Millisecond,
+		LongPressDuration:    500 * time.Millisecond,
+		SwipeMinDistance:     50,
+		SwipeMaxDuration:     500 * time.Millisecond,
+		TwoFingerMinDistance: 8,
+	}
+}
+
+// mouseID identifies the left mouse button among the pointers; touch IDs are never
+// negative.
+const mouseID = -1
+
+type pointer struct {
+	id             int
+	startX, startY float64
+	x, y           float64
+	start          time.Time
+	moved          bool // moved further than a tap may
+	twoFinger      bool // was part of a pinch or pan, so it is no tap or swipe
+	longPressed    bool
+}
+
+// Recognizer turns mouse and touch events into gestures.
+type Recognizer struct {
+	cfg      Config
+	pointers []*pointer
+
+	// The two touches of a pinch or pan
+	pinching         bool
+	lastDist         float64
+	lastCX, lastCY   float64
+	startDist        float64
+	startCX, startCY float64
+
+	lastTap    time.Time
+	lastTapX   float64
+	lastTapY   float64
+	lastTapSet bool
+
+	// Mouse button events carry no position, so the mouse pointer uses the last move's
+	mouseX, mouseY float64
+}
+
+// NewRecognizer creates a Recognizer with the given thresholds.
+func NewRecognizer(cfg Config) *Recognizer {
+	return &Recognizer{cfg: cfg}
+}
+
+// Feed passes an event to the Recognizer and returns the gestures it completes. Events
+// without a Time are treated as happening now.
+func (r *Recognizer) Feed(evt input.Event) []Gesture {
+	now := evt.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	gestures := r.Tick(now)
+
+	x, y := float64(evt.X), float64(evt.Y)
+	switch evt.Type {
+	case input.MousePress:
+		if evt.MouseButton == input.MouseButtonLeft {
+			r.down(mouseID, r.mouseX, r.mouseY, now)
+		}
+	case input.MouseMove:
+		r.mouseX, r.mouseY = x, y
+		gestures = append(gestures, r.move(mouseID, x, y, now)...)
+	case input.MouseRelease:
+		if evt.MouseButton == input.MouseButtonLeft {
+			gestures = append(gestures, r.up(mouseID, now, false)...)
+		}
+	case input.TouchStart:
+		r.down(evt.TouchID, x, y, now)
+	case input.TouchMove:
+		gestures = append(gestures, r.move(evt.TouchID, x, y, now)...)
+	case input.TouchEnd, input.TouchCancel:
+		gestures = append(gestures, r.up(evt.TouchID, now, evt.Type == input.TouchCancel)...)
+	}
+	return gestures
+}
+
+// Tick returns the long presses of pointers held still until now. Call it each frame,
+// since a long press can complete without any event.
+func (r *Recognizer) Tick(now time.Time) []Gesture {
+	var gestures []Gesture
+	for _, p := range r.pointers {
+		if p.moved || p.twoFinger || p.longPressed || now.Sub(p.start) < r.cfg.LongPressDuration {
+			continue
+		}
+		p.longPressed = true
+		gestures = append(gestures, Gesture{Kind: LongPress, X: p.x, Y: p.y, Time: now})
+	}
+	return gestures
+}
+
+func (r *Recognizer) find(id int) *pointer {
+	for _, p := range r.pointers {
+		if p.id == id {
+			return p
+		}
+	}
+	return nil
+}
+
+func (r *Recognizer) down(id int, x, y float64, now time.Time) {
+	if r.find(id) != nil {
+		return
+	}
+	p := &pointer{id: id, startX: x, startY: y, x: x, y: y, start: now}
+	r.pointers = append(r.pointers, p)
+	if len(r.pointers) == 2 {
+		r.startTwoFinger()
+	}
+}
+
+func (r *Recognizer) move(id int, x, y float64, now time.Time) []Gesture {
+	p := r.find(id)
+	if p == nil {
+		return nil
+	}
+	p.x, p.y = x, y
+	if math.Hypot(x-p.startX, y-p.startY) > r.cfg.TapMaxDistance {
+		p.moved = true
+	}
+	if len(r.pointers) != 2 {
+		return nil
+	}
+
+	dist, cx, cy := r.twoFinger()
+	if !r.pinching {
+		if math.Abs(dist-r.startDist) < r.cfg.TwoFingerMinDistance &&
+			math.Hypot(cx-r.startCX, cy-r.startCY) < r.cfg.TwoFingerMinDistance {
+			return nil
+		}
+		r.pinching = true
+	}
+
+	var gestures []Gesture
+	if r.lastDist > 0 && dist != r.lastDist {
+		gestures = append(gestures, Gesture{Kind: Pinch, X: cx, Y: cy, Scale: dist / r.lastDist, Time: now})
+	}
+	if cx != r.lastCX || cy != r.lastCY {
+		gestures = append(gestures, Gesture{Kind: Pan, X: cx, Y: cy, DX: cx - r.lastCX, DY: cy - r.lastCY, Time: now})
+	}
+	r.lastDist, r.lastCX, r.lastCY = dist, cx, cy
+	return gestures
+}
+
+func (r *Recognizer) up(id int, now time.Time, canceled bool) []Gesture {
+	p := r.find(id)
+	if p == nil {
+		return nil
+	}
+	for i, o := range r.pointers {
+		if o == p {
+			r.pointers = append(r.pointers[:i], r.pointers[i+1:]...)
+			break
+		}
+	}
+	switch len(r.pointers) {
+	case 2:
+		// A third touch lifted; pinch and pan with the two left
+		r.startTwoFinger()
+	case 1, 0:
+		r.pinching = false
+	}
+	if canceled || p.twoFinger || p.longPressed {
+		return nil
+	}
+
+	held := now.Sub(p.start)
+	dx, dy := p.x-p.startX, p.y-p.startY
+	switch {
+	case !p.moved && held <= r.cfg.TapMaxDuration:
+		gestures := []Gesture{{Kind: Tap, X: p.x, Y: p.y, Time: now}}
+		if r.lastTapSet && now.Sub(r.lastTap) <= r.cfg.DoubleTapMaxInterval &&
+			math.Hypot(p.x-r.lastTapX, p.y-r.lastTapY) <= r.cfg.TapMaxDistance {
+			gestures = append(gestures, Gesture{Kind: DoubleTap, X: p.x, Y: p.y, Time: now})
+			// A third tap starts a new double tap
+			r.lastTapSet = false
+		} else {
+			r.lastTap, r.lastTapX, r.lastTapY, r.lastTapSet = now, p.x, p.y, true
+		}
+		return gestures
+	case math.Hypot(dx, dy) >= r.cfg.SwipeMinDistance && held <= r.cfg.SwipeMaxDuration:
+		return []Gesture{{Kind: Swipe, X: p.startX, Y: p.startY, DX: dx, DY: dy, Time: now}}
+	}
+	return nil
+}
+
+// startTwoFinger begins tracking the two touches held for a pinch or pan.
+func (r *Recognizer) startTwoFinger() {
+	for _, p := range r.pointers {
+		p.twoFinger = true
+	}
+	r.pinching = false
+	r.startDist, r.startCX, r.startCY = r.twoFinger()
+	r.lastDist, r.lastCX, r.lastCY = r.startDist, r.startCX, r.startCY
+}
+
+// twoFinger returns the distance between the two touches held and the point between
+// them.
+func (r *Recognizer) twoFinger() (dist, cx, cy float64) {
+	a, b := r.pointers[0], r.pointers[1]
+	return math.Hypot(b.x-a.x, b.y-a.y), (a.x + b.x) / 2, (a.y + b.y) / 2
+}
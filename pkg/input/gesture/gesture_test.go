@@ -0,0 +1,294 @@
+package gesture
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/dfirebaugh/hlg/pkg/input"
+)
+
+var epoch = time.Unix(1000, 0)
+
+func at(ms int) time.Time {
+	return epoch.Add(time.Duration(ms) * time.Millisecond)
+}
+
+func touchStart(id, x, y, ms int) input.Event {
+	return input.Event{Type: input.TouchStart, TouchID: id, X: x, Y: y, Time: at(ms)}
+}
+
+func touchMove(id, x, y, ms int) input.Event {
+	return input.Event{Type: input.TouchMove, TouchID: id, X: x, Y: y, Time: at(ms)}
+}
+
+func touchEnd(id, x, y, ms int) input.Event {
+	return input.Event{Type: input.TouchEnd, TouchID: id, X: x, Y: y, Time: at(ms)}
+}
+
+func mouseMove(x, y, ms int) input.Event {
+	return input.Event{Type: input.MouseMove, X: x, Y: y, Time: at(ms)}
+}
+
+func mouseButton(eventType input.EventType, ms int) input.Event {
+	return input.Event{Type: eventType, MouseButton: input.MouseButtonLeft, Time: at(ms)}
+}
+
+// feed passes events to a Recognizer with the default thresholds and returns every
+// gesture recognized.
+func feed(events ...input.Event) []Gesture {
+	r := NewRecognizer(DefaultConfig())
+	var gestures []Gesture
+	for _, evt := range events {
+		gestures = append(gestures, r.Feed(evt)...)
+	}
+	return gestures
+}
+
+func kinds(gestures []Gesture) []Kind {
+	var ks []Kind
+	for _, g := range gestures {
+		ks = append(ks, g.Kind)
+	}
+	return ks
+}
+
+func sameKinds(a, b []Kind) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSinglePointerGestures(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []input.Event
+		want   []Kind
+	}{
+		{
+			name:   "tap",
+			events: []input.Event{touchStart(1, 50, 50, 0), touchEnd(1, 50, 50, 100)},
+			want:   []Kind{Tap},
+		},
+		{
+			name:   "tap held for the longest tap",
+			events: []input.Event{touchStart(1, 50, 50, 0), touchEnd(1, 50, 50, 250)},
+			want:   []Kind{Tap},
+		},
+		{
+			name:   "press held too long for a tap",
+			events: []input.Event{touchStart(1, 50, 50, 0), touchEnd(1, 50, 50, 251)},
+			want:   nil,
+		},
+		{
+			name: "tap moved by the furthest a tap may",
+			events: []input.Event{
+				touchStart(1, 50, 50, 0), touchMove(1, 60, 50, 50), touchEnd(1, 60, 50, 100),
+			},
+			want: []Kind{Tap},
+		},
+		{
+			name: "press moved too far for a tap",
+			events: []input.Event{
+				touchStart(1, 50, 50, 0), touchMove(1, 61, 50, 50), touchEnd(1, 61, 50, 100),
+			},
+			want: nil,
+		},
+		{
+			name: "double tap",
+			events: []input.Event{
+				touchStart(1, 50, 50, 0), touchEnd(1, 50, 50, 50),
+				touchStart(2, 52, 50, 300), touchEnd(2, 52, 50, 350),
+			},
+			want: []Kind{Tap, Tap, DoubleTap},
+		},
+		{
+			name: "taps too far apart in time for a double tap",
+			events: []input.Event{
+				touchStart(1, 50, 50, 0), touchEnd(1, 50, 50, 50),
+				touchStart(2, 50, 50, 320), touchEnd(2, 50, 50, 351),
+			},
+			want: []Kind{Tap, Tap},
+		},
+		{
+			name: "taps too far apart in space for a double tap",
+			events: []input.Event{
+				touchStart(1, 50, 50, 0), touchEnd(1, 50, 50, 50),
+				touchStart(2, 80, 50, 100), touchEnd(2, 80, 50, 150),
+			},
+			want: []Kind{Tap, Tap},
+		},
+		{
+			name: "third tap starts a new double tap",
+			events: []input.Event{
+				touchStart(1, 50, 50, 0), touchEnd(1, 50, 50, 50),
+				touchStart(2, 50, 50, 100), touchEnd(2, 50, 50, 150),
+				touchStart(3, 50, 50, 200), touchEnd(3, 50, 50, 250),
+			},
+			want: []Kind{Tap, Tap, DoubleTap, Tap},
+		},
+		{
+			name: "long press without a tap on release",
+			events: []input.Event{
+				touchStart(1, 50, 50, 0), touchMove(1, 51, 50, 500), touchEnd(1, 51, 50, 600),
+			},
+			want: []Kind{LongPress},
+		},
+		{
+			name: "swipe",
+			events: []input.Event{
+				touchStart(1, 100, 100, 0), touchMove(1, 150, 100, 100), touchEnd(1, 150, 100, 200),
+			},
+			want: []Kind{Swipe},
+		},
+		{
+			name: "movement too short for a swipe",
+			events: []input.Event{
+				touchStart(1, 100, 100, 0), touchMove(1, 149, 100, 100), touchEnd(1, 149, 100, 200),
+			},
+			want: nil,
+		},
+		{
+			name: "movement too slow for a swipe",
+			events: []input.Event{
+				touchStart(1, 100, 100, 0), touchMove(1, 200, 100, 100), touchEnd(1, 200, 100, 501),
+			},
+			want: nil,
+		},
+		{
+			name: "canceled touch",
+			events: []input.Event{
+				touchStart(1, 50, 50, 0),
+				{Type: input.TouchCancel, TouchID: 1, X: 50, Y: 50, Time: at(100)},
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kinds(feed(tt.events...)); !sameKinds(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLongPressTick(t *testing.T) {
+	r := NewRecognizer(DefaultConfig())
+	r.Feed(touchStart(1, 40, 30, 0))
+	if got := r.Tick(at(499)); len(got) != 0 {
+		t.Fatalf("long press before its duration: %v", got)
+	}
+	got := r.Tick(at(500))
+	if len(got) != 1 || got[0].Kind != LongPress || got[0].X != 40 || got[0].Y != 30 {
+		t.Fatalf("got %v, want one long press at (40, 30)", got)
+	}
+	if got := r.Tick(at(900)); len(got) != 0 {
+		t.Fatalf("long press repeated: %v", got)
+	}
+}
+
+func TestMouseUsesCursorPosition(t *testing.T) {
+	got := feed(
+		mouseMove(200, 150, 0),
+		mouseButton(input.MousePress, 10), mouseButton(input.MouseRelease, 60),
+		mouseButton(input.MousePress, 100), mouseButton(input.MouseRelease, 150),
+	)
+	if !sameKinds(kinds(got), []Kind{Tap, Tap, DoubleTap}) {
+		t.Fatalf("got %v, want Tap, Tap, DoubleTap", kinds(got))
+	}
+	for _, g := range got {
+		if g.X != 200 || g.Y != 150 {
+			t.Errorf("%v at (%v, %v), want (200, 150)", g.Kind, g.X, g.Y)
+		}
+	}
+
+	got = feed(
+		mouseMove(300, 100, 0),
+		mouseButton(input.MousePress, 10), mouseMove(220, 100, 100), mouseButton(input.MouseRelease, 150),
+	)
+	if len(got) != 1 || got[0].Kind != Swipe {
+		t.Fatalf("got %v, want one swipe", kinds(got))
+	}
+	if got[0].X != 300 || got[0].DX != -80 || got[0].Direction() != Left {
+		t.Errorf("swipe from %v by %v (%v), want from 300 by -80 to the left", got[0].X, got[0].DX, got[0].Direction())
+	}
+
+	if got := feed(mouseMove(300, 100, 0), mouseButton(input.MousePress, 10), mouseButton(input.MouseRelease, 50)); len(got) != 1 || got[0].Kind != Tap {
+		t.Errorf("click away from the origin: got %v, want one tap", kinds(got))
+	}
+}
+
+func TestPinchAndPan(t *testing.T) {
+	got := feed(
+		touchStart(1, 100, 100, 0), touchStart(2, 200, 100, 0),
+		touchMove(2, 300, 100, 50),
+	)
+	if !sameKinds(kinds(got), []Kind{Pinch, Pan}) {
+		t.Fatalf("got %v, want Pinch, Pan", kinds(got))
+	}
+	if got[0].Scale != 2 || got[0].X != 200 || got[0].Y != 100 {
+		t.Errorf("pinch by %v about (%v, %v), want 2 about (200, 100)", got[0].Scale, got[0].X, got[0].Y)
+	}
+	if got[1].DX != 50 || got[1].DY != 0 {
+		t.Errorf("pan by (%v, %v), want (50, 0)", got[1].DX, got[1].DY)
+	}
+}
+
+func TestTwoFingerPan(t *testing.T) {
+	got := feed(
+		touchStart(1, 100, 100, 0), touchStart(2, 200, 100, 0),
+		touchMove(1, 100, 120, 20), touchMove(2, 200, 120, 40),
+		touchEnd(1, 100, 120, 60), touchEnd(2, 200, 120, 60),
+	)
+	var dy float64
+	for _, g := range got {
+		switch g.Kind {
+		case Pan:
+			dy += g.DY
+		case Tap, Swipe:
+			t.Errorf("two-finger touches gave a %v", g.Kind)
+		}
+	}
+	if dy != 20 {
+		t.Errorf("panned by %v, want 20", dy)
+	}
+}
+
+func TestTwoFingerThreshold(t *testing.T) {
+	// The center moves 3.5 and the distance 7, both under the minimum of 8
+	got := feed(touchStart(1, 100, 100, 0), touchStart(2, 200, 100, 0), touchMove(2, 207, 100, 20))
+	if len(got) != 0 {
+		t.Fatalf("resting fingers gave %v", kinds(got))
+	}
+
+	// Moving on past the minimum reports the movement since the touches began
+	got = feed(
+		touchStart(1, 100, 100, 0), touchStart(2, 200, 100, 0),
+		touchMove(2, 207, 100, 20), touchMove(2, 208, 100, 30),
+	)
+	if !sameKinds(kinds(got), []Kind{Pinch, Pan}) {
+		t.Fatalf("got %v, want Pinch, Pan", kinds(got))
+	}
+	if math.Abs(got[0].Scale-1.08) > 1e-9 {
+		t.Errorf("pinch by %v, want 1.08", got[0].Scale)
+	}
+}
+
+func TestThirdTouchLifted(t *testing.T) {
+	// With a third touch lifted, the two left pinch from where they are
+	got := feed(
+		touchStart(1, 0, 0, 0), touchStart(2, 100, 0, 0), touchStart(3, 500, 500, 0),
+		touchEnd(1, 0, 0, 10),
+		touchMove(3, 500, 600, 20),
+	)
+	if len(got) == 0 || got[0].Kind != Pinch {
+		t.Fatalf("got %v, want a pinch", kinds(got))
+	}
+}
@@ -3,21 +3,33 @@ package input
 type InputState struct {
 	KeyState           map[Key]bool
 	KeyJustPressed     map[Key]bool
+	KeyJustRepeated    map[Key]bool
+	Mods               ModifierKey // modifier keys held
 	ButtonState        map[MouseButton]bool
 	ButtonJustPressed  map[MouseButton]bool
 	ButtonJustReleased map[MouseButton]bool
 	CursorPosition     struct{ X, Y int }
 	ScrollCallback     func(x, y float64)
+	ScrollX, ScrollY   float64 // wheel movement this frame; positive y scrolls up
+	MouseDX, MouseDY   float64 // mouse movement this frame, in window pixels
 	TypedRunes         []rune
+	Events             []Event // events received this frame, in order
+	Touches            []Touch // touches held, and those that ended this frame
+	EmulateMouse       bool    // whether the first touch also drives the mouse
+
+	mouseTouch   bool // a touch is driving the mouse
+	mouseTouchID int
 }
 
 func NewInputState() *InputState {
 	return &InputState{
 		KeyState:           make(map[Key]bool),
 		KeyJustPressed:     make(map[Key]bool),
+		KeyJustRepeated:    make(map[Key]bool),
 		ButtonState:        make(map[MouseButton]bool),
 		ButtonJustPressed:  make(map[MouseButton]bool),
 		ButtonJustReleased: make(map[MouseButton]bool),
+		EmulateMouse:       true,
 	}
 }
 
@@ -46,6 +58,48 @@ func (is *InputState) ReleaseKey(keyCode Key) {
 	is.KeyState[keyCode] = false
 }
 
+// IsKeyRepeated returns true if the specified key sent a repeat this frame because it
+// is held down. Text editing repeats on IsKeyJustPressed or IsKeyRepeated.
+func (is *InputState) IsKeyRepeated(keyCode Key) bool {
+	repeated, exists := is.KeyJustRepeated[keyCode]
+	return exists && repeated
+}
+
+// RepeatKey simulates a key repeat of a held key
+func (is *InputState) RepeatKey(keyCode Key) {
+	is.KeyState[keyCode] = true
+	is.KeyJustRepeated[keyCode] = true
+}
+
+// ModifiersHeld returns true if all of the given modifier keys are held, e.g.
+// ModifiersHeld(ModControl|ModShift)
+func (is *InputState) ModifiersHeld(mods ModifierKey) bool {
+	return is.Mods&mods == mods
+}
+
+// SetModifiers sets the modifier keys held, as reported by a key or mouse button event
+func (is *InputState) SetModifiers(mods ModifierKey) {
+	is.Mods = mods
+}
+
+// SetKeyModifiers sets the modifier keys held after a key event has updated the key
+// state. Platforms report the modifiers from before the event, so the modifier of a
+// modifier key is taken from whether its left or right key is held, and releasing one
+// Shift leaves Shift held while the other is down.
+func (is *InputState) SetKeyModifiers(mods ModifierKey, key Key) {
+	mod := KeyModifier(key)
+	if mod == 0 {
+		is.Mods = mods
+		return
+	}
+	left, right := modifierKeys(mod)
+	mods &^= mod
+	if is.IsKeyPressed(left) || is.IsKeyPressed(right) {
+		mods |= mod
+	}
+	is.Mods = mods
+}
+
 // IsButtonPressed returns true if the specified mouse button is currently pressed
 func (is *InputState) IsButtonPressed(buttonCode MouseButton) bool {
 	pressed, exists := is.ButtonState[buttonCode]
@@ -91,11 +145,39 @@ func (is *InputState) SetScrollCallback(cb func(x, y float64)) {
 	is.ScrollCallback = cb
 }
 
+// AddScroll adds mouse wheel movement to the movement of this frame
+func (is *InputState) AddScroll(x, y float64) {
+	is.ScrollX += x
+	is.ScrollY += y
+}
+
+// AddMouseDelta adds mouse movement to the movement of this frame
+func (is *InputState) AddMouseDelta(dx, dy float64) {
+	is.MouseDX += dx
+	is.MouseDY += dy
+}
+
+// GetMouseDelta returns the mouse movement of this frame in window pixels
+func (is *InputState) GetMouseDelta() (dx, dy float64) {
+	return is.MouseDX, is.MouseDY
+}
+
+// GetScrollDelta returns the mouse wheel movement of this frame. Positive y scrolls up
+// and positive x scrolls left.
+func (is *InputState) GetScrollDelta() (x, y float64) {
+	return is.ScrollX, is.ScrollY
+}
+
 // ResetJustPressed resets the just pressed/released state at the end of a frame
 func (is *InputState) ResetJustPressed() {
+	is.ScrollX, is.ScrollY = 0, 0
+	is.MouseDX, is.MouseDY = 0, 0
 	for key := range is.KeyJustPressed {
 		is.KeyJustPressed[key] = false
 	}
+	for key := range is.KeyJustRepeated {
+		is.KeyJustRepeated[key] = false
+	}
 	for button := range is.ButtonJustPressed {
 		is.ButtonJustPressed[button] = false
 	}
@@ -103,6 +185,8 @@ func (is *InputState) ResetJustPressed() {
 		is.ButtonJustReleased[button] = false
 	}
 	is.TypedRunes = is.TypedRunes[:0]
+	is.Events = is.Events[:0]
+	is.resetTouches()
 }
 
 // AddTypedRune adds a rune to the typed runes list
@@ -110,7 +194,46 @@ func (is *InputState) AddTypedRune(r rune) {
 	is.TypedRunes = append(is.TypedRunes, r)
 }
 
+// AddEvent adds an event to the events received this frame
+func (is *InputState) AddEvent(evt Event) {
+	is.Events = append(is.Events, evt)
+}
+
+// GetEvents returns the events received this frame, in order
+func (is *InputState) GetEvents() []Event {
+	return is.Events
+}
+
 // GetTypedRunes returns the runes typed this frame
 func (is *InputState) GetTypedRunes() []rune {
 	return is.TypedRunes
 }
+
+// KeyModifier returns the modifier of a modifier key, such as ModShift for
+// KeyLeftShift, or 0 for other keys.
+func KeyModifier(key Key) ModifierKey {
+	switch key {
+	case KeyLeftShift, KeyRightShift:
+		return ModShift
+	case KeyLeftControl, KeyRightControl:
+		return ModControl
+	case KeyLeftAlt, KeyRightAlt:
+		return ModAlt
+	case KeyLeftSuper, KeyRightSuper:
+		return ModSuper
+	}
+	return 0
+}
+
+// modifierKeys returns the left and right keys of a modifier.
+func modifierKeys(mod ModifierKey) (left, right Key) {
+	switch mod {
+	case ModShift:
+		return KeyLeftShift, KeyRightShift
+	case ModControl:
+		return KeyLeftControl, KeyRightControl
+	case ModAlt:
+		return KeyLeftAlt, KeyRightAlt
+	}
+	return KeyLeftSuper, KeyRightSuper
+}
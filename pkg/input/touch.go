@@ -0,0 +1,78 @@
+package input
+
+import (
+	"slices"
+	"time"
+)
+
+// Touch is a finger or stylus held on a touch screen.
+type Touch struct {
+	ID             int
+	X, Y           int // position in logical coordinates
+	StartX, StartY int // position where the touch began
+	StartTime      time.Time
+
+	JustStarted bool // the touch began this frame
+	JustEnded   bool // the touch lifted or was canceled this frame
+	Canceled    bool // the touch was interrupted by the platform rather than lifted
+}
+
+// StartTouch adds a touch. With EmulateMouse set and no other touch driving the mouse,
+// the touch also moves the cursor and presses the left mouse button.
+func (is *InputState) StartTouch(id, x, y int, t time.Time) {
+	is.Touches = append(is.Touches, Touch{
+		ID: id, X: x, Y: y, StartX: x, StartY: y, StartTime: t, JustStarted: true,
+	})
+	if is.EmulateMouse && !is.mouseTouch {
+		is.mouseTouch = true
+		is.mouseTouchID = id
+		is.CursorPosition.X, is.CursorPosition.Y = x, y
+		is.PressButton(MouseButtonLeft)
+	}
+}
+
+// MoveTouch moves a held touch
+func (is *InputState) MoveTouch(id, x, y int) {
+	if touch := is.findTouch(id); touch != nil {
+		touch.X, touch.Y = x, y
+	}
+	if is.mouseTouch && is.mouseTouchID == id {
+		is.CursorPosition.X, is.CursorPosition.Y = x, y
+	}
+}
+
+// EndTouch ends a held touch, releasing the mouse button if the touch drives the mouse.
+// The touch stays in Touches until the end of the frame.
+func (is *InputState) EndTouch(id int, canceled bool) {
+	if touch := is.findTouch(id); touch != nil {
+		touch.JustEnded = true
+		touch.Canceled = canceled
+	}
+	if is.mouseTouch && is.mouseTouchID == id {
+		is.mouseTouch = false
+		is.ReleaseButton(MouseButtonLeft)
+	}
+}
+
+// GetTouches returns the touches held and those that ended this frame, in the order
+// they began
+func (is *InputState) GetTouches() []Touch {
+	return is.Touches
+}
+
+func (is *InputState) findTouch(id int) *Touch {
+	for i := range is.Touches {
+		if is.Touches[i].ID == id && !is.Touches[i].JustEnded {
+			return &is.Touches[i]
+		}
+	}
+	return nil
+}
+
+// resetTouches removes the touches that ended this frame at the end of a frame
+func (is *InputState) resetTouches() {
+	is.Touches = slices.DeleteFunc(is.Touches, func(t Touch) bool { return t.JustEnded })
+	for i := range is.Touches {
+		is.Touches[i].JustStarted = false
+	}
+}
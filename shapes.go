@@ -88,6 +88,7 @@ func flushBatch() {
 		clipRects = append(clipRects, frameClipRects...)
 
 		vertices = append(vertices, frameVertices...)
+		uploadTextStyles()
 		SubmitDrawBufferWithClipRects(vertices, clipRects)
 
 		// Render immediately to preserve draw order with Shapes
@@ -128,6 +129,39 @@ func Text(s string, x, y int, fontSize float32, c color.Color) {
 	framePrimitives = append(framePrimitives, primitives...)
 }
 
+// FrameGlyphDrawer draws MSDF glyphs into the current BeginDraw/EndDraw batch.
+// It implements graphics.AtlasGlyphDrawer, so glyphs from a font's fallback chain
+// can be drawn with Font.DrawText alongside the primary font's glyphs.
+type FrameGlyphDrawer struct{}
+
+// DrawGlyph draws a glyph from the active atlas (slot 0).
+func (FrameGlyphDrawer) DrawGlyph(x0, y0, x1, y1 float32, u0, v0, u1, v1 float32, c color.Color) {
+	FrameGlyphDrawer{}.DrawAtlasGlyph(x0, y0, x1, y1, u0, v0, u1, v1, 0, c)
+}
+
+// DrawAtlasGlyph draws a glyph from the given MSDF atlas slot.
+func (FrameGlyphDrawer) DrawAtlasGlyph(x0, y0, x1, y1 float32, u0, v0, u1, v1 float32, atlasSlot int, c color.Color) {
+	if x1 <= x0 || y1 <= y0 {
+		return
+	}
+	primitive := graphics.Primitive{
+		X:         x0,
+		Y:         y0,
+		W:         x1 - x0,
+		H:         y1 - y0,
+		Color:     toRGBA(c),
+		OpCode:    graphics.OpCodeMSDF,
+		AtlasSlot: float32(atlasSlot),
+		Extra:     [4]float32{u0, v0, u1 - u0, v1 - v0},
+		ClipRect:  getCurrentClipRect(),
+	}
+	if framePrimitives == nil {
+		SubmitPrimitives([]graphics.Primitive{primitive})
+		return
+	}
+	framePrimitives = append(framePrimitives, primitive)
+}
+
 // RoundedRect draws a filled rounded rectangle.
 // Must be called between BeginDraw() and EndDraw().
 func RoundedRect(x, y, width, height, cornerRadius int, c color.Color) {
@@ -267,6 +301,33 @@ func MeasureText(text string, fontSize float32) float32 {
 	return defaultFont.MeasureText(text, fontSize)
 }
 
+// TextIndexAtPoint returns the byte index of the caret position closest to (x, y)
+// in text drawn with the default font, relative to where the text is drawn.
+// See Font.IndexAtPoint.
+func TextIndexAtPoint(text string, fontSize, x, y float32) int {
+	if defaultFont == nil {
+		font, err := LoadDefaultFont()
+		if err != nil {
+			return 0
+		}
+		SetDefaultFont(font)
+	}
+	return defaultFont.IndexAtPoint(text, fontSize, x, y)
+}
+
+// TextCaretRect returns the caret rectangle before the byte index in text drawn with
+// the default font, relative to where the text is drawn. See Font.CaretRect.
+func TextCaretRect(text string, fontSize float32, index int) (x, y, w, h float32) {
+	if defaultFont == nil {
+		font, err := LoadDefaultFont()
+		if err != nil {
+			return 0, 0, 0, 0
+		}
+		SetDefaultFont(font)
+	}
+	return defaultFont.CaretRect(text, fontSize, index)
+}
+
 // PolygonFromVertices creates a polygon shape using a specified array of vertices.
 // The vertices should be defined with their positions and colors. The function converts
 // the input vertices from the local Vertex type to the graphics.Vertex type required by
@@ -405,6 +466,14 @@ func SetMSDFAtlas(atlasImg image.Image, pxRange float64) {
 	hlg.graphicsBackend.SetMSDFAtlas(atlasImg, pxRange)
 }
 
+// SetMSDFAtlasSlot sets the MSDF font atlas for an additional atlas slot.
+// Slot 0 is the atlas set by SetMSDFAtlas. Primitives choose a slot through
+// their AtlasSlot field, which lets glyphs from several fonts share a batch.
+func SetMSDFAtlasSlot(slot int, atlasImg image.Image, pxRange float64) {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetMSDFAtlasSlot(slot, atlasImg, pxRange)
+}
+
 // SetMSDFMode sets the MSDF rendering mode.
 // Mode 0: median(RGB) - MSDF reconstruction for sharp corners (default)
 // Mode 1: alpha channel only (true SDF fallback)
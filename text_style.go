@@ -0,0 +1,142 @@
+package hlg
+
+import (
+	"image/color"
+
+	"github.com/dfirebaugh/hlg/graphics"
+)
+
+// TextStyle describes the effects TextStyled draws with MSDF text.
+// Zero values disable an effect, so TextStyle{Color: c} draws plain text.
+//
+// Outline, glow and blur are computed in the fragment shader from the distance field,
+// so they cost no extra draw calls but cannot reach further from the glyph edge than
+// the atlas distance range allows: about FontConfig.PixelRange/2 atlas pixels, scaled
+// by fontSize/FontConfig.Size. Raise PixelRange for wider effects on small text.
+type TextStyle struct {
+	Color color.Color // fill color; nil or transparent draws only the effects
+
+	OutlineWidth float32 // outline width in pixels outside the glyph edge
+	OutlineColor color.Color
+
+	ShadowOffsetX, ShadowOffsetY float32 // shadow offset in pixels
+	ShadowBlur                   float32 // shadow edge softness in pixels
+	ShadowColor                  color.Color
+
+	GlowRadius float32 // distance in pixels beyond the outline the glow fades over
+	GlowColor  color.Color
+}
+
+// frameTextStyles is the text style table shared by all styled text drawn in a frame.
+// Styles keep their index until the table is reset after the frame is rendered, because
+// backends that render once per frame draw every batch with the last uploaded table.
+var frameTextStyles = []graphics.TextStyleParams{{}}
+
+// frameTextStylesDirty is set when frameTextStyles changed since it was last uploaded.
+var frameTextStylesDirty bool
+
+// addTextStyle returns the index of params in the frame's style table, adding it if needed.
+// Plain text uses index 0. Once the table holds graphics.MaxTextStyles styles, new styles
+// fall back to plain text for the rest of the frame.
+func addTextStyle(params graphics.TextStyleParams) float32 {
+	if params == (graphics.TextStyleParams{}) {
+		return 0
+	}
+	for i, style := range frameTextStyles {
+		if style == params {
+			return float32(i)
+		}
+	}
+	if len(frameTextStyles) >= graphics.MaxTextStyles {
+		return 0
+	}
+	frameTextStyles = append(frameTextStyles, params)
+	frameTextStylesDirty = true
+	return float32(len(frameTextStyles) - 1)
+}
+
+// uploadTextStyles sends the style table to the backend if it changed.
+func uploadTextStyles() {
+	if !frameTextStylesDirty {
+		return
+	}
+	hlg.graphicsBackend.SetTextStyles(frameTextStyles)
+	frameTextStylesDirty = false
+}
+
+// resetTextStyles empties the style table once a frame has been rendered.
+func resetTextStyles() {
+	frameTextStyles = frameTextStyles[:1]
+}
+
+// styleColor converts c to RGBA, treating nil as transparent.
+func styleColor(c color.Color) [4]float32 {
+	if c == nil {
+		return [4]float32{}
+	}
+	return toRGBA(c)
+}
+
+// TextStyled draws text like Text, with an outline, drop shadow and glow.
+// The shadow is drawn as a second copy of the glyphs beneath the text; outline and
+// glow are drawn by the MSDF shader in the same pass as the fill.
+// Up to graphics.MaxTextStyles-1 distinct styles can be used per frame (a style with a
+// shadow takes two); further styles are drawn as plain text.
+// Should be called between BeginDraw() and EndDraw().
+func TextStyled(s string, x, y int, fontSize float32, style TextStyle) {
+	if defaultFont == nil {
+		font, err := LoadDefaultFont()
+		if err != nil {
+			return
+		}
+		SetDefaultFont(font)
+		font.SetAsActiveAtlas()
+	}
+	glyphs := defaultFont.RenderTextPrimitives(s, float32(x), float32(y), fontSize, color.Transparent)
+	if len(glyphs) == 0 {
+		return
+	}
+
+	clipRect := getCurrentClipRect()
+	primitives := make([]graphics.Primitive, 0, 2*len(glyphs))
+
+	shadowColor := styleColor(style.ShadowColor)
+	if shadowColor[3] > 0 {
+		// The shadow covers the outline too, so give it the same outline in the shadow color
+		shadowStyle := addTextStyle(graphics.TextStyleParams{
+			OutlineColor: shadowColor,
+			OutlineWidth: style.OutlineWidth,
+			Softness:     style.ShadowBlur,
+		})
+		for _, glyph := range glyphs {
+			glyph.X += style.ShadowOffsetX
+			glyph.Y += style.ShadowOffsetY
+			glyph.Color = shadowColor
+			glyph.Radius = shadowStyle
+			glyph.ClipRect = clipRect
+			primitives = append(primitives, glyph)
+		}
+	}
+
+	textStyle := addTextStyle(graphics.TextStyleParams{
+		OutlineColor: styleColor(style.OutlineColor),
+		GlowColor:    styleColor(style.GlowColor),
+		OutlineWidth: style.OutlineWidth,
+		GlowRadius:   style.GlowRadius,
+	})
+	fillColor := styleColor(style.Color)
+	for _, glyph := range glyphs {
+		glyph.Color = fillColor
+		glyph.Radius = textStyle
+		glyph.ClipRect = clipRect
+		primitives = append(primitives, glyph)
+	}
+
+	if framePrimitives == nil {
+		// Fallback to immediate mode if not in batched mode
+		uploadTextStyles()
+		SubmitPrimitives(primitives)
+		return
+	}
+	framePrimitives = append(framePrimitives, primitives...)
+}
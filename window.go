@@ -0,0 +1,60 @@
+package hlg
+
+import "image"
+
+// SetWindowIcon sets the window's icon from images of different sizes, such as 16x16,
+// 32x32 and 48x48; the system picks the size closest to what it needs. In browsers the
+// largest becomes the page's favicon.
+func SetWindowIcon(images []image.Image) {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetWindowIcon(images)
+}
+
+// SetWindowPosition moves the window's top left corner to (x, y) in screen coordinates.
+// Listen for input.WindowMoved events with OnEvent to follow moves made by the user.
+// Browsers do not let pages move, resize or raise their window, so in wasm builds this
+// and the window controls below do nothing.
+func SetWindowPosition(x, y int) {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetWindowPosition(x, y)
+}
+
+// SetWindowSizeLimits limits the size the user can resize the window to, in screen
+// coordinates. Zero leaves a limit unset.
+func SetWindowSizeLimits(minWidth, minHeight, maxWidth, maxHeight int) {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetWindowSizeLimits(minWidth, minHeight, maxWidth, maxHeight)
+}
+
+// SetAlwaysOnTop keeps the window above other windows.
+func SetAlwaysOnTop(v bool) {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.SetAlwaysOnTop(v)
+}
+
+// Minimize minimizes the window. An input.WindowMinimized event follows, and an
+// input.WindowRestored event when it is shown again.
+func Minimize() {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.MinimizeWindow()
+}
+
+// Maximize maximizes the window, followed by an input.WindowMaximized event.
+func Maximize() {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.MaximizeWindow()
+}
+
+// Restore restores a minimized or maximized window, followed by an
+// input.WindowRestored event.
+func Restore() {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.RestoreWindow()
+}
+
+// RequestAttention highlights the window without taking focus, such as by flashing its
+// taskbar entry, to tell the player something happened while they were away.
+func RequestAttention() {
+	ensureSetupCompletion()
+	hlg.graphicsBackend.RequestAttention()
+}